@@ -0,0 +1,102 @@
+package gomockserver
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// z99 is the number of standard deviations above the mean of the 99th percentile of a normal distribution.
+const z99 = 2.326347874
+
+// DelayDistribution represents a source of delays to apply to a response.
+type DelayDistribution interface {
+	// Sample returns the next delay to apply.
+	Sample() time.Duration
+}
+
+// DelayDistributionFunc is a function type that implements the `DelayDistribution` interface.
+type DelayDistributionFunc func() time.Duration
+
+func (f DelayDistributionFunc) Sample() time.Duration {
+	return f()
+}
+
+// FixedDelay returns a `DelayDistribution` that always produces the same delay.
+func FixedDelay(delay time.Duration) DelayDistribution {
+	return DelayDistributionFunc(func() time.Duration {
+		return delay
+	})
+}
+
+// UniformDelay returns a `DelayDistribution` that produces delays chosen uniformly at random between the minimum and
+// maximum provided.
+func UniformDelay(min, max time.Duration) DelayDistribution {
+	return DelayDistributionFunc(func() time.Duration {
+		if max <= min {
+			return min
+		}
+
+		return min + time.Duration(rand.Int63n(int64(max-min))) //nolint:gosec
+	})
+}
+
+// LogNormalDelay returns a `DelayDistribution` that produces delays following a log-normal distribution, which is a
+// good model of real-world latencies. The distribution is described by its median delay and its 99th percentile delay,
+// which must be larger than the median.
+func LogNormalDelay(median, p99 time.Duration) DelayDistribution {
+	mu := math.Log(float64(median))
+	sigma := (math.Log(float64(p99)) - mu) / z99
+
+	return DelayDistributionFunc(func() time.Duration {
+		return time.Duration(math.Exp(mu + sigma*rand.NormFloat64())) //nolint:gosec
+	})
+}
+
+// ResponseDelay returns a `ResponseBuilder` that delays the response by the given amount before sending anything.
+func ResponseDelay(delay time.Duration) ResponseBuilder {
+	return ResponseDelayDistribution(FixedDelay(delay))
+}
+
+// ResponseRandomDelay returns a `ResponseBuilder` that delays the response by a random amount between the minimum and
+// maximum provided before sending anything.
+func ResponseRandomDelay(min, max time.Duration) ResponseBuilder {
+	return ResponseDelayDistribution(UniformDelay(min, max))
+}
+
+// ResponseDelayDistribution returns a `ResponseBuilder` that delays the response by an amount taken from the provided
+// distribution before sending anything. A new delay is taken for every response.
+func ResponseDelayDistribution(delay DelayDistribution) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		r.Delay = delay.Sample()
+	})
+}
+
+// ResponseChunkDelay returns a `ResponseBuilder` that sends the response body in chunks of the given size, with a delay
+// taken from the provided distribution between each chunk.
+func ResponseChunkDelay(chunkSize int, delay DelayDistribution) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		r.ChunkSize = chunkSize
+		r.ChunkDelay = delay
+	})
+}
+
+// sleepContext waits for the given delay, returning early if the context is cancelled first.
+// This returns `true` if the full delay elapsed, or `false` if the context was cancelled.
+func sleepContext(ctx context.Context, delay time.Duration) bool {
+	if delay <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,89 @@
+package gomockserver
+
+import "fmt"
+
+// expectation represents the number of times that a `Match` is expected to be used to respond to requests.
+type expectation struct {
+	min int
+	max int
+}
+
+// unbounded indicates that an expectation has no upper limit on the number of requests.
+const unbounded = -1
+
+// met checks if the provided number of requests satisfies this expectation.
+func (e expectation) met(count int) bool {
+	return count >= e.min && (e.max == unbounded || count <= e.max)
+}
+
+func (e expectation) String() string {
+	switch {
+	case e.max == 0:
+		return "no requests"
+	case e.min == e.max:
+		return fmt.Sprintf("exactly %d requests", e.min)
+	case e.max == unbounded:
+		return fmt.Sprintf("at least %d requests", e.min)
+	case e.min == 0:
+		return fmt.Sprintf("at most %d requests", e.max)
+	default:
+		return fmt.Sprintf("between %d and %d requests", e.min, e.max)
+	}
+}
+
+func (m *Match) expect(update func(e *expectation)) *Match {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.expectation == nil {
+		m.expectation = &expectation{min: 0, max: unbounded}
+	}
+
+	update(m.expectation)
+
+	return m
+}
+
+// Times declares that this match is expected to be used to respond to exactly the given number of requests.
+// This is verified when the test finishes.
+func (m *Match) Times(n int) *Match {
+	return m.expect(func(e *expectation) {
+		e.min = n
+		e.max = n
+	})
+}
+
+// AtLeast declares that this match is expected to be used to respond to at least the given number of requests.
+// This is verified when the test finishes, and can be combined with `AtMost`.
+func (m *Match) AtLeast(n int) *Match {
+	return m.expect(func(e *expectation) {
+		e.min = n
+	})
+}
+
+// AtMost declares that this match is expected to be used to respond to at most the given number of requests.
+// This is verified when the test finishes, and can be combined with `AtLeast`.
+func (m *Match) AtMost(n int) *Match {
+	return m.expect(func(e *expectation) {
+		e.max = n
+	})
+}
+
+// Never declares that this match is expected to never be used to respond to any requests.
+// This is verified when the test finishes.
+func (m *Match) Never() *Match {
+	return m.Times(0)
+}
+
+// unmetExpectation will check if this match has an expectation that has not been met, and if so return a description
+// of it.
+func (m *Match) unmetExpectation() (string, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.expectation == nil || m.expectation.met(m.count) {
+		return "", false
+	}
+
+	return fmt.Sprintf("expected %s, got %d", m.expectation, m.count), true
+}
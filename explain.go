@@ -0,0 +1,152 @@
+package gomockserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// MatchRuleExplainer is an optional interface that a `MatchRule` can implement to describe why it did or did not match
+// a request. These descriptions are used to explain why an unmatched request was not handled by any `Match`.
+type MatchRuleExplainer interface {
+	MatchRule
+	// Explain will check to see if the provided HTTP request matches this rule, and return a human-readable reason for
+	// the result.
+	Explain(r *http.Request) (bool, string)
+}
+
+// MatchRuleExplainerFunc is a function type that implements the `MatchRuleExplainer` interface.
+// This allows for simple functions to be used in place of the interface.
+type MatchRuleExplainerFunc func(*http.Request) (bool, string)
+
+func (m MatchRuleExplainerFunc) Matches(r *http.Request) bool {
+	matched, _ := m(r)
+
+	return matched
+}
+
+func (m MatchRuleExplainerFunc) Explain(r *http.Request) (bool, string) {
+	return m(r)
+}
+
+// explainRule will check if the provided rule matches the request, and describe why.
+// Rules that can't explain themselves are given a generic description.
+func explainRule(rule MatchRule, r *http.Request) (bool, string) {
+	if explainer, ok := rule.(MatchRuleExplainer); ok {
+		return explainer.Explain(r)
+	}
+
+	return rule.Matches(r), fmt.Sprintf("custom rule %T", rule)
+}
+
+// explainRules will explain each of the provided rules in turn, returning how many of them passed and the reason for
+// each one.
+func explainRules(r *http.Request, rules []MatchRule) (int, []string) {
+	passed := 0
+	reasons := make([]string, 0, len(rules))
+
+	for _, rule := range rules {
+		rewindBody(r)
+
+		restore := savePathParams(r)
+		matched, reason := explainRule(rule, r)
+
+		restore()
+
+		if matched {
+			passed++
+		}
+
+		reasons = append(reasons, fmt.Sprintf("%s: %s", resultLabel(matched), reason))
+	}
+
+	return passed, reasons
+}
+
+// flattenRules will expand any nested `MatchRules` into a single list of rules, so that each can be reported on
+// individually.
+func flattenRules(rules MatchRules) []MatchRule {
+	result := []MatchRule{}
+
+	for _, rule := range rules {
+		if nested, ok := rule.(MatchRules); ok {
+			result = append(result, flattenRules(nested)...)
+		} else {
+			result = append(result, rule)
+		}
+	}
+
+	return result
+}
+
+func resultLabel(matched bool) string {
+	if matched {
+		return "PASS"
+	}
+
+	return "FAIL"
+}
+
+// ruleResult represents the outcome of checking a single rule against an unmatched request.
+type ruleResult struct {
+	matched bool
+	reason  string
+}
+
+// matchResult represents the outcome of checking a single `Match` against an unmatched request.
+type matchResult struct {
+	index  int
+	rules  []ruleResult
+	passed int
+}
+
+// explainMatches will check every provided `Match` against the request, and return the results ordered so that the
+// one that came closest to matching is first.
+func explainMatches(matches []*Match, r *http.Request) []matchResult {
+	results := make([]matchResult, 0, len(matches))
+
+	for i, match := range matches {
+		resetRequest(r)
+
+		result := matchResult{index: i + 1}
+
+		for _, rule := range match.explain(r) {
+			result.rules = append(result.rules, rule)
+
+			if rule.matched {
+				result.passed++
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score() > results[j].score()
+	})
+
+	return results
+}
+
+// score returns the proportion of rules that passed for this match, as a measure of how close it came to matching.
+func (m matchResult) score() float64 {
+	if len(m.rules) == 0 {
+		return 0
+	}
+
+	return float64(m.passed) / float64(len(m.rules))
+}
+
+func (m matchResult) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Match %d (%d of %d rules passed):", m.index, m.passed, len(m.rules))
+
+	for _, rule := range m.rules {
+		reason := strings.ReplaceAll(rule.reason, "\n", "\n        ")
+		fmt.Fprintf(&b, "\n    %s: %s", resultLabel(rule.matched), reason)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package gomockserver
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// Fault represents a way in which the mock server can deliberately misbehave instead of sending a valid response.
+type Fault int
+
+const (
+	// FaultNone will send a normal, well-formed response. This is the default.
+	FaultNone Fault = iota
+	// FaultCloseConnection will close the connection without sending any response at all.
+	FaultCloseConnection
+	// FaultConnectionReset will send the status line, the headers and half of the body, and then reset the connection.
+	FaultConnectionReset
+	// FaultContentLengthMismatch will send a `Content-Length` header that is larger than the body, and then close the
+	// connection after sending the body.
+	FaultContentLengthMismatch
+	// FaultMalformedResponse will send garbage bytes instead of a valid HTTP response, and then close the connection.
+	FaultMalformedResponse
+	// FaultStall will never send anything, holding the connection open until the client gives up or the server is
+	// closed.
+	FaultStall
+)
+
+// malformedResponse is the garbage sent instead of a valid response by `FaultMalformedResponse`.
+var malformedResponse = []byte("\x00\x01\x02 This is not a valid HTTP response \xff\xfe\xfd\r\n\r\n")
+
+// ResponseFault returns a `ResponseBuilder` that will make the response misbehave in the given way instead of being
+// sent normally. Any delay applied to the response will still happen before the fault.
+//
+// Faults work by taking over the underlying connection, so they are only fully supported over HTTP/1.x. Where this is
+// not possible the request is aborted instead.
+func ResponseFault(fault Fault) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		r.Fault = fault
+	})
+}
+
+// writeFault will write the response to the client, misbehaving in the way described by the response's fault.
+func (r Response) writeFault(ctx context.Context, w http.ResponseWriter) {
+	if r.Fault == FaultStall {
+		<-ctx.Done()
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	defer conn.Close()
+
+	switch r.Fault {
+	case FaultConnectionReset:
+		r.writeHead(buf.Writer, len(r.Body))
+		_, _ = buf.Write(r.Body[:len(r.Body)/2])
+		_ = buf.Flush()
+
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			_ = tcp.SetLinger(0)
+		}
+	case FaultContentLengthMismatch:
+		r.writeHead(buf.Writer, 2*len(r.Body)+1)
+		_, _ = buf.Write(r.Body)
+	case FaultMalformedResponse:
+		_, _ = buf.Write(malformedResponse)
+	case FaultNone, FaultCloseConnection, FaultStall:
+	}
+
+	_ = buf.Flush()
+}
+
+// writeHead will write the status line and headers of the response directly to the connection, claiming that the body
+// has the given length.
+func (r Response) writeHead(w *bufio.Writer, contentLength int) {
+	fmt.Fprintf(w, "HTTP/1.1 %d %s\r\n", r.Status, http.StatusText(r.Status))
+
+	headers := r.Headers.Clone()
+	headers.Set("Content-Length", strconv.Itoa(contentLength))
+	_ = headers.Write(w)
+
+	_, _ = w.WriteString("\r\n")
+}
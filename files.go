@@ -0,0 +1,134 @@
+package gomockserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// errIsDirectory is returned when trying to serve a directory as a file.
+var errIsDirectory = errors.New("is a directory")
+
+// serveContent will populate the response with the provided file content, as served by `http.ServeContent`. This
+// infers the content type from the file name, and handles range and conditional requests.
+func serveContent(r *Response, req *http.Request, name string, modTime time.Time, content []byte) {
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("ETag", fmt.Sprintf(`"%x"`, sha256.Sum256(content)))
+
+	http.ServeContent(recorder, req, name, modTime, bytes.NewReader(content))
+
+	for name, values := range recorder.Header() {
+		r.Headers[name] = values
+	}
+
+	r.Status = recorder.Code
+	r.Body = recorder.Body.Bytes()
+}
+
+// serveFileError will populate the response to describe a failure to read a file.
+func serveFileError(r *Response, name string, err error) {
+	r.Status = http.StatusInternalServerError
+	r.Body = []byte(fmt.Sprintf("Failed to read response file %s: %v", name, err))
+}
+
+// readFSFile will read the named file from the filesystem, returning its contents and modification time.
+func readFSFile(fsys fs.FS, name string) ([]byte, time.Time, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if info.IsDir() {
+		return nil, time.Time{}, fmt.Errorf("%s: %w", name, errIsDirectory)
+	}
+
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return content, info.ModTime(), nil
+}
+
+// ResponseFile will use the contents of the named file as the response body. The file is read every
+// time a response is built, so changes to it are picked up immediately.
+// The content-type header is inferred from the file extension, and range requests and conditional requests using
+// `If-None-Match` and `If-Modified-Since` are handled automatically. If the file can not be read then the response
+// will be an `HTTP 500 Internal Server Error` describing the failure.
+func ResponseFile(filename string) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		info, err := os.Stat(filename)
+		if err != nil {
+			serveFileError(r, filename, err)
+
+			return
+		}
+
+		content, err := ioutil.ReadFile(filename)
+		if err != nil {
+			serveFileError(r, filename, err)
+
+			return
+		}
+
+		serveContent(r, req, filepath.Base(filename), info.ModTime(), content)
+	})
+}
+
+// ResponseFS will use the contents of the named file from the provided filesystem as the response body, in the same
+// way as `ResponseFile`.
+func ResponseFS(fsys fs.FS, name string) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		content, modTime, err := readFSFile(fsys, name)
+		if err != nil {
+			serveFileError(r, name, err)
+
+			return
+		}
+
+		serveContent(r, req, path.Base(name), modTime, content)
+	})
+}
+
+// responseDirectory builds a `ResponseBuilder` that serves the file from the filesystem that is named by the path
+// captured by a trailing wildcard in a `MatchURLPathTemplate`, using `index.html` for directories. If there is no such
+// file then the response is an `HTTP 404 Not Found`.
+func responseDirectory(fsys fs.FS) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		name := strings.Trim(path.Clean("/"+PathParam(req, PathWildcard)), "/")
+		if name == "" {
+			name = "."
+		}
+
+		if info, err := fs.Stat(fsys, name); err == nil && info.IsDir() {
+			name = path.Join(name, "index.html")
+		}
+
+		content, modTime, err := readFSFile(fsys, name)
+		if err != nil {
+			r.Status = http.StatusNotFound
+			r.Body = []byte(http.StatusText(http.StatusNotFound))
+
+			return
+		}
+
+		serveContent(r, req, path.Base(name), modTime, content)
+	})
+}
+
+func (s *server) MountDirectory(prefix string, fsys fs.FS) *Match {
+	return s.Matches(
+		MatchAny(MatchMethod(http.MethodGet), MatchMethod(http.MethodHead)),
+		MatchURLPathTemplate(strings.TrimSuffix(prefix, "/")+"/*"),
+	).RespondsWith(responseDirectory(fsys))
+}
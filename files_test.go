@@ -0,0 +1,165 @@
+package gomockserver_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/sazzer/gomockserver"
+)
+
+// fileRequest will make a GET request to the provided URL with the given headers, returning the response and body.
+func fileRequest(t *testing.T, url string, headers map[string]string) (*http.Response, string) {
+	t.Helper()
+	is := is.New(t)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	is.NoErr(err)
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	is.NoErr(err)
+
+	return resp, string(body)
+}
+
+func TestResponseFile(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPath("/data")).
+		RespondsWith(gomockserver.ResponseFile("testdata/fixtures/data.json"))
+
+	resp, body := fileRequest(t, server.URL()+"/data", nil)
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(resp.Header.Get("Content-Type"), "application/json")
+	is.Equal(body, "{\"hello\":\"world\"}\n")
+
+	etag := resp.Header.Get("ETag")
+	is.True(etag != "")
+
+	resp, body = fileRequest(t, server.URL()+"/data", map[string]string{"Range": "bytes=2-6"})
+	is.Equal(resp.StatusCode, http.StatusPartialContent)
+	is.Equal(resp.Header.Get("Content-Range"), "bytes 2-6/18")
+	is.Equal(body, "hello")
+
+	resp, body = fileRequest(t, server.URL()+"/data", map[string]string{"If-None-Match": etag})
+	is.Equal(resp.StatusCode, http.StatusNotModified)
+	is.Equal(body, "")
+
+	resp, _ = fileRequest(t, server.URL()+"/data", map[string]string{
+		"If-Modified-Since": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+	})
+	is.Equal(resp.StatusCode, http.StatusNotModified)
+}
+
+func TestResponseFileMissing(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Matches().RespondsWith(gomockserver.ResponseFile("testdata/fixtures/missing.json"))
+
+	resp, body := fileRequest(t, server.URL(), nil)
+
+	is.Equal(resp.StatusCode, http.StatusInternalServerError)
+	is.True(strings.HasPrefix(body, "Failed to read response file testdata/fixtures/missing.json: "))
+}
+
+func TestResponseFS(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	fsys := fstest.MapFS{
+		"users/1.json": &fstest.MapFile{Data: []byte(`{"id":1}`), ModTime: time.Now()},
+		"notes.txt":    &fstest.MapFile{Data: []byte("Some notes")},
+	}
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPath("/users/1")).RespondsWith(gomockserver.ResponseFS(fsys, "users/1.json"))
+	server.Matches(gomockserver.MatchURLPath("/notes")).RespondsWith(gomockserver.ResponseFS(fsys, "notes.txt"))
+	server.Matches(gomockserver.MatchURLPath("/users")).RespondsWith(gomockserver.ResponseFS(fsys, "users"))
+
+	resp, body := fileRequest(t, server.URL()+"/users/1", nil)
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(resp.Header.Get("Content-Type"), "application/json")
+	is.Equal(body, `{"id":1}`)
+
+	resp, body = fileRequest(t, server.URL()+"/notes", nil)
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(resp.Header.Get("Content-Type"), "text/plain; charset=utf-8")
+	is.Equal(body, "Some notes")
+
+	resp, _ = fileRequest(t, server.URL()+"/users", nil)
+	is.Equal(resp.StatusCode, http.StatusInternalServerError)
+}
+
+func TestMountDirectory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{name: "Index", path: "/static/", status: http.StatusOK, contentType: "text/html; charset=utf-8",
+			body: "<html><body>Home</body></html>\n"},
+		{name: "Index without slash", path: "/static", status: http.StatusOK, contentType: "text/html; charset=utf-8",
+			body: "<html><body>Home</body></html>\n"},
+		{name: "File", path: "/static/data.json", status: http.StatusOK, contentType: "application/json",
+			body: "{\"hello\":\"world\"}\n"},
+		{name: "Nested file", path: "/static/nested/page.txt", status: http.StatusOK,
+			contentType: "text/plain; charset=utf-8", body: "Hello, World\n"},
+		{name: "Directory without index", path: "/static/nested", status: http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8", body: "Not Found"},
+		{name: "Missing file", path: "/static/missing.txt", status: http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8", body: "Not Found"},
+		{name: "Escaping directory", path: "/static/%2E%2E/files_test.go", status: http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8", body: "Not Found"},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t)
+			defer server.Close()
+
+			match := server.MountDirectory("/static", os.DirFS("testdata/fixtures"))
+
+			resp, body := fileRequest(t, server.URL()+tt.path, nil)
+
+			is.Equal(resp.StatusCode, tt.status)
+			is.Equal(resp.Header.Get("Content-Type"), tt.contentType)
+			is.Equal(body, tt.body)
+			is.Equal(match.Count(), 1)
+		})
+	}
+}
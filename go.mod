@@ -3,6 +3,9 @@ module github.com/sazzer/gomockserver
 go 1.16
 
 require (
+	github.com/gorilla/websocket v1.5.0
 	github.com/matryer/is v1.4.0
 	github.com/nsf/jsondiff v0.0.0-20210303162244-6ea32392771e
+	golang.org/x/net v0.0.0-20210226172049-e18ecbb05110
+	gopkg.in/yaml.v3 v3.0.1
 )
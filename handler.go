@@ -1,35 +1,141 @@
 package gomockserver
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"testing"
+	"sync"
 )
 
 type handler struct {
-	t              *testing.T
-	matches        []*Match
-	unmatchedCount int
+	reporter    Reporter
+	mutex       sync.RWMutex
+	matches     []*Match
+	journal     RecordedRequests
+	scenarios   *scenarios
+	maxBodySize int64
+	closed      chan struct{}
+	closeOnce   sync.Once
+	active      sync.WaitGroup
+}
+
+// close signals to any requests that are still in progress that the server is shutting down.
+func (h *handler) close() {
+	h.closeOnce.Do(func() {
+		close(h.closed)
+	})
+}
+
+// wait blocks until every request that is in progress has finished, including those that have taken over their
+// connection.
+func (h *handler) wait() {
+	h.active.Wait()
+}
+
+// requestContext returns a context for handling the request that is cancelled either when the request is finished or
+// when the server is shutting down, whichever happens first.
+func (h *handler) requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(r.Context())
+
+	go func() {
+		select {
+		case <-h.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// addMatch will register a new `Match` to be checked against incoming requests.
+func (h *handler) addMatch(match *Match) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.matches = append(h.matches, match)
+}
+
+// getMatches will return a copy of every registered `Match`, in the order they were registered.
+func (h *handler) getMatches() []*Match {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return append([]*Match{}, h.matches...)
+}
+
+// record will add a new request to the journal of requests that have been received.
+func (h *handler) record(request RecordedRequest) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.journal = append(h.journal, request)
+}
+
+// getJournal will return a copy of every request that has been received, in the order they were received.
+func (h *handler) getJournal() RecordedRequests {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return append(RecordedRequests{}, h.journal...)
 }
 
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	for _, match := range h.matches {
+	h.active.Add(1)
+	defer h.active.Done()
+
+	captured, err := captureRequest(r, h.maxBodySize)
+	if errors.Is(err, ErrBodyTooLarge) {
+		h.reporter.Errorf("Request body for %s %s is larger than the maximum of %d bytes", r.Method, r.RequestURI,
+			h.maxBodySize)
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+
+		return
+	} else if err != nil {
+		h.reporter.Errorf("Failed to read request body for %s %s: %v", r.Method, r.RequestURI, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	r = captured
+	record := recordRequest(r)
+	matches := h.getMatches()
+
+	for _, match := range matches {
+		resetRequest(r)
+
 		if match.Matches(r) {
-			match.count++
+			record.Match = match
+
+			responses, ok := match.handled(record)
+			if !ok {
+				continue
+			}
+
+			h.record(record)
 
 			response := Response{
 				Status:  http.StatusOK,
 				Headers: http.Header{},
 			}
 
-			match.responses.PopulateResponse(&response, r)
+			rewindBody(r)
+			responses.PopulateResponse(&response, r)
+
+			ctx, cancel := h.requestContext(r)
+			defer cancel()
 
-			response.Write(w)
+			response.write(ctx, w)
 
 			return
 		}
 	}
 
+	record.Match = nil
+	h.record(record)
+
 	requestOutput := fmt.Sprintf("%s %s", r.Method, r.RequestURI)
 
 	for name, values := range r.Header {
@@ -38,9 +144,15 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	h.t.Logf("Unmatched request: %s", requestOutput)
+	if len(matches) > 0 {
+		requestOutput = fmt.Sprintf("%s\n\nClosest matches:", requestOutput)
+
+		for _, result := range explainMatches(matches, r) {
+			requestOutput = fmt.Sprintf("%s\n%s", requestOutput, result)
+		}
+	}
 
-	h.unmatchedCount++
+	h.reporter.Logf("Unmatched request: %s", requestOutput)
 
 	http.NotFound(w, r)
 }
@@ -0,0 +1,36 @@
+package gomockserver
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// WithHTTP2 will allow the mock server to serve requests using HTTP/2 as well as HTTP/1.1.
+// When used with TLS, the protocol is negotiated using ALPN. Otherwise the server will accept HTTP/2 requests without
+// TLS - known as h2c - and `MockServer.Client` will return a client that makes all requests this way.
+func WithHTTP2() Option {
+	return func(c *config) {
+		c.http2 = true
+	}
+}
+
+// newH2CTransport builds an HTTP transport that makes requests using HTTP/2 without TLS, connecting with the provided
+// dial function.
+func newH2CTransport(dial func(ctx context.Context, network, addr string) (net.Conn, error)) http.RoundTripper {
+	return &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			return dial(context.Background(), network, addr)
+		},
+	}
+}
+
+// h2cHandler wraps the provided handler so that it will accept HTTP/2 requests without TLS.
+func h2cHandler(handler http.Handler) http.Handler {
+	return h2c.NewHandler(handler, &http2.Server{})
+}
@@ -0,0 +1,118 @@
+package gomockserver_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/sazzer/gomockserver"
+)
+
+func TestHTTP2(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options []gomockserver.Option
+		proto   string
+	}{
+		{name: "HTTP/1.1", options: []gomockserver.Option{}, proto: "HTTP/1.1"},
+		{name: "HTTP/1.1 over TLS", options: []gomockserver.Option{gomockserver.WithTLS()}, proto: "HTTP/1.1"},
+		{name: "h2c", options: []gomockserver.Option{gomockserver.WithHTTP2()}, proto: "HTTP/2.0"},
+		{name: "HTTP/2 over TLS", options: []gomockserver.Option{gomockserver.WithTLS(), gomockserver.WithHTTP2()},
+			proto: "HTTP/2.0"},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t, tt.options...)
+			defer server.Close()
+
+			match := server.Matches(gomockserver.MatchProto(tt.proto)).
+				RespondsWith(gomockserver.ResponseBody([]byte("Hello")))
+
+			resp, err := server.Client().Get(server.URL() + "/testing") //nolint:noctx
+			is.NoErr(err)
+
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			is.NoErr(err)
+
+			is.Equal(resp.StatusCode, http.StatusOK)
+			is.Equal(resp.Proto, tt.proto)
+			is.Equal(string(body), "Hello")
+
+			is.Equal(match.Count(), 1)
+			is.Equal(server.Requests().Last().Proto, tt.proto)
+			is.True(server.Requests().Last().Matches(gomockserver.MatchProto(tt.proto)))
+		})
+	}
+}
+
+func TestMatchProtoMismatch(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
+	defer server.Close()
+
+	match := server.Matches(gomockserver.MatchProto("HTTP/2.0"))
+
+	resp, err := server.Client().Get(server.URL()) //nolint:noctx
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusNotFound)
+	is.Equal(match.Count(), 0)
+	is.Equal(server.UnmatchedCount(), 1)
+}
+
+func TestResponseTrailers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options []gomockserver.Option
+	}{
+		{name: "HTTP/1.1", options: []gomockserver.Option{}},
+		{name: "h2c", options: []gomockserver.Option{gomockserver.WithHTTP2()}},
+		{name: "HTTP/2 over TLS", options: []gomockserver.Option{gomockserver.WithTLS(), gomockserver.WithHTTP2()}},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t, tt.options...)
+			defer server.Close()
+
+			server.Matches().
+				RespondsWith(gomockserver.ResponseBody([]byte("Hello")),
+					gomockserver.ResponseTrailer("Grpc-Status", "0"),
+					gomockserver.ResponseTrailer("Grpc-Message", "OK"))
+
+			resp, err := server.Client().Get(server.URL()) //nolint:noctx
+			is.NoErr(err)
+
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			is.NoErr(err)
+
+			is.Equal(string(body), "Hello")
+			is.Equal(resp.Trailer.Get("Grpc-Status"), "0")
+			is.Equal(resp.Trailer.Get("Grpc-Message"), "OK")
+		})
+	}
+}
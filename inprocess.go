@@ -0,0 +1,111 @@
+package gomockserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// errPipeListenerClosed is returned when connecting to an in-process server that has been closed.
+var errPipeListenerClosed = errors.New("in-process server has been closed")
+
+// WithInProcessTransport will start the mock server without opening any network sockets. Instead, requests are
+// sent to it over in-memory connections by the client returned from `MockServer.Client`, or by using the
+// `http.RoundTripper` returned from `MockServer.Transport` in another client.
+// Because no network address is used, the server URL will be "http://localhost", and the host of any request URL is
+// ignored. This can not be combined with TLS.
+func WithInProcessTransport() Option {
+	return func(c *config) {
+		c.inProcess = true
+	}
+}
+
+// pipeAddr is the address of an in-process connection.
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string {
+	return "pipe"
+}
+
+func (pipeAddr) String() string {
+	return "pipe"
+}
+
+// pipeListener is a `net.Listener` that accepts in-memory connections created by calling `dial`.
+type pipeListener struct {
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+	})
+
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+// dial will create a new in-memory connection to the listener, ignoring the network and address requested.
+func (l *pipeListener) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, server := net.Pipe()
+
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.closed:
+		client.Close()
+		server.Close()
+
+		return nil, errPipeListenerClosed
+	case <-ctx.Done():
+		client.Close()
+		server.Close()
+
+		return nil, ctx.Err()
+	}
+}
+
+// newInProcessClient builds an HTTP client that sends every request to the given in-process listener.
+func newInProcessClient(listener *pipeListener, http2 bool) *http.Client {
+	if http2 {
+		return &http.Client{Transport: newH2CTransport(listener.dial)}
+	}
+
+	return &http.Client{Transport: &http.Transport{DialContext: listener.dial}}
+}
+
+func (s *server) Transport() http.RoundTripper {
+	client := s.Client()
+	if client == nil {
+		return nil
+	}
+
+	if client.Transport == nil {
+		return http.DefaultTransport
+	}
+
+	return client.Transport
+}
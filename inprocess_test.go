@@ -0,0 +1,203 @@
+package gomockserver_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/sazzer/gomockserver"
+)
+
+func TestInProcessTransport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options []gomockserver.Option
+		proto   string
+	}{
+		{name: "HTTP/1.1", options: []gomockserver.Option{}, proto: "HTTP/1.1"},
+		{name: "h2c", options: []gomockserver.Option{gomockserver.WithHTTP2()}, proto: "HTTP/2.0"},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t, append(tt.options, gomockserver.WithInProcessTransport(),
+				gomockserver.WithAllowUnmatchedRequests())...)
+			defer server.Close()
+
+			match := server.Matches(gomockserver.MatchRequest(http.MethodPost, "/testing"),
+				gomockserver.MatchProto(tt.proto)).
+				RespondsWith(gomockserver.ResponseJSON(map[string]string{"hello": "world"}))
+
+			is.Equal(server.URL(), "http://localhost")
+
+			resp, err := server.Client().Post(server.URL()+"/testing", "text/plain", //nolint:noctx
+				strings.NewReader("Hello"))
+			is.NoErr(err)
+
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			is.NoErr(err)
+
+			is.Equal(resp.StatusCode, http.StatusOK)
+			is.Equal(string(body), `{"hello":"world"}`)
+
+			client := &http.Client{Transport: server.Transport()}
+
+			resp, err = client.Get("http://example.com/unmatched") //nolint:noctx
+			is.NoErr(err)
+
+			defer resp.Body.Close()
+
+			is.Equal(resp.StatusCode, http.StatusNotFound)
+
+			is.Equal(match.Count(), 1)
+			is.Equal(string(match.Requests().Last().Body), "Hello")
+			is.Equal(server.UnmatchedCount(), 1)
+			is.Equal(server.UnmatchedRequests().Last().URL.Path, "/unmatched")
+		})
+	}
+}
+
+func TestInProcessTransportConcurrent(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t, gomockserver.WithInProcessTransport())
+	defer server.Close()
+
+	match := server.Matches(gomockserver.MatchURLPathTemplate("/items/{id}")).
+		RespondsWith(gomockserver.ResponseStatus(http.StatusNoContent))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			resp, err := server.Client().Get(fmt.Sprintf("%s/items/%d", server.URL(), i)) //nolint:noctx
+			if err == nil {
+				resp.Body.Close()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	is.Equal(match.Count(), 20)
+}
+
+func TestInProcessTransportStreaming(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t, gomockserver.WithInProcessTransport())
+	defer server.Close()
+
+	release := make(chan struct{})
+
+	server.Matches(gomockserver.MatchURLPath("/stream")).
+		RespondsWith(gomockserver.ResponseStreamGenerator(func(index int) ([]byte, error) {
+			switch index {
+			case 0:
+				return []byte("first"), nil
+			case 1:
+				<-release
+
+				return []byte("second"), nil
+			default:
+				return nil, io.EOF
+			}
+		}))
+
+	resp, err := server.Client().Get(server.URL() + "/stream") //nolint:noctx
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	chunk := make([]byte, 5)
+	_, err = io.ReadFull(resp.Body, chunk)
+	is.NoErr(err)
+	is.Equal(string(chunk), "first")
+
+	close(release)
+
+	rest, err := ioutil.ReadAll(resp.Body)
+	is.NoErr(err)
+	is.Equal(string(rest), "second")
+}
+
+func TestInProcessTransportFaults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		fault gomockserver.Fault
+	}{
+		{name: "Close connection", fault: gomockserver.FaultCloseConnection},
+		{name: "Connection reset", fault: gomockserver.FaultConnectionReset},
+		{name: "Content length mismatch", fault: gomockserver.FaultContentLengthMismatch},
+		{name: "Malformed response", fault: gomockserver.FaultMalformedResponse},
+		{name: "Stall", fault: gomockserver.FaultStall},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t, gomockserver.WithInProcessTransport())
+			defer server.Close()
+
+			server.Matches(gomockserver.MatchURLPath("/fault")).
+				RespondsWith(gomockserver.ResponseBody([]byte("Hello, World")),
+					gomockserver.ResponseFault(tt.fault))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL()+"/fault", nil)
+			is.NoErr(err)
+
+			resp, err := server.Client().Do(req)
+			if err == nil {
+				defer resp.Body.Close()
+
+				_, err = ioutil.ReadAll(resp.Body)
+			}
+
+			is.True(err != nil)
+		})
+	}
+}
+
+func TestInProcessTransportClosed(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t, gomockserver.WithInProcessTransport())
+	transport := server.Transport()
+
+	server.Close()
+
+	_, err := (&http.Client{Transport: transport}).Get("http://localhost") //nolint:noctx,bodyclose
+	is.True(err != nil)
+}
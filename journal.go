@@ -0,0 +1,131 @@
+package gomockserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RecordedRequest represents a single request that was received by the mock server.
+type RecordedRequest struct {
+	Method string
+	URL    *url.URL
+	// Proto is the protocol version that the request was made with, for example "HTTP/1.1" or "HTTP/2.0".
+	Proto     string
+	Header    http.Header
+	Body      []byte
+	Timestamp time.Time
+	// Match is the `Match` that was used to respond to the request, or `nil` if the request was not matched.
+	Match *Match
+}
+
+// recordRequest will build a new `RecordedRequest` from the incoming request.
+func recordRequest(r *http.Request) RecordedRequest {
+	uri := *r.URL
+
+	return RecordedRequest{
+		Method:    r.Method,
+		URL:       &uri,
+		Proto:     r.Proto,
+		Header:    r.Header.Clone(),
+		Body:      RequestBody(r),
+		Timestamp: time.Now(),
+	}
+}
+
+// Request will build an `*http.Request` representing the recorded request.
+// This allows any `MatchRule` to be used to check the details of the request after it has been handled.
+func (r RecordedRequest) Request() *http.Request {
+	state := &requestState{
+		body:       r.Body,
+		pathParams: map[string]string{},
+	}
+
+	proto := r.Proto
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+
+	major, minor, _ := http.ParseHTTPVersion(proto)
+
+	req := &http.Request{
+		Method:     r.Method,
+		URL:        r.URL,
+		RequestURI: r.URL.RequestURI(),
+		Proto:      proto,
+		ProtoMajor: major,
+		ProtoMinor: minor,
+		Header:     r.Header.Clone(),
+		Host:       r.URL.Host,
+	}
+
+	req = req.WithContext(context.WithValue(context.Background(), requestStateKey{}, state))
+	rewindBody(req)
+
+	return req
+}
+
+// Matches will check if the recorded request passes every one of the provided rules.
+func (r RecordedRequest) Matches(rules ...MatchRule) bool {
+	return MatchRules(rules).Matches(r.Request())
+}
+
+// DecodeJSON will decode the body of the recorded request as JSON into the provided value.
+func (r RecordedRequest) DecodeJSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+func (r RecordedRequest) String() string {
+	return fmt.Sprintf("%s %s", r.Method, r.URL.RequestURI())
+}
+
+// RecordedRequests represents a list of requests that were received by the mock server, in the order they were
+// received.
+type RecordedRequests []RecordedRequest
+
+// Matching returns only the recorded requests that pass every one of the provided rules.
+func (r RecordedRequests) Matching(rules ...MatchRule) RecordedRequests {
+	result := RecordedRequests{}
+
+	for _, req := range r {
+		if req.Matches(rules...) {
+			result = append(result, req)
+		}
+	}
+
+	return result
+}
+
+// Unmatched returns only the recorded requests that were not matched by any `Match`.
+func (r RecordedRequests) Unmatched() RecordedRequests {
+	result := RecordedRequests{}
+
+	for _, req := range r {
+		if req.Match == nil {
+			result = append(result, req)
+		}
+	}
+
+	return result
+}
+
+// First returns the first recorded request, or `nil` if there are none.
+func (r RecordedRequests) First() *RecordedRequest {
+	if len(r) == 0 {
+		return nil
+	}
+
+	return &r[0]
+}
+
+// Last returns the most recently recorded request, or `nil` if there are none.
+func (r RecordedRequests) Last() *RecordedRequest {
+	if len(r) == 0 {
+		return nil
+	}
+
+	return &r[len(r)-1]
+}
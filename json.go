@@ -2,7 +2,7 @@ package gomockserver
 
 import (
 	"encoding/json"
-	"io/ioutil"
+	"fmt"
 	"net/http"
 
 	"github.com/nsf/jsondiff"
@@ -18,29 +18,28 @@ func ResponseJSON(data interface{}) ResponseBuilder {
 	}
 }
 
-func matchJSON(r *http.Request, expected interface{}) jsondiff.Difference {
+func matchJSON(r *http.Request, expected interface{}) (jsondiff.Difference, string) {
 	expectedJSON, err := json.Marshal(expected)
 	if err != nil {
-		return jsondiff.NoMatch
+		return jsondiff.NoMatch, fmt.Sprintf("unable to encode expected JSON: %v", err)
 	}
 
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		return jsondiff.NoMatch
-	}
+	body := RequestBody(r)
 
 	options := jsondiff.DefaultJSONOptions()
-	diff, _ := jsondiff.Compare(body, expectedJSON, &options)
+	diff, description := jsondiff.Compare(body, expectedJSON, &options)
 
-	return diff
+	return diff, description
 }
 
 // MatchJSONFull will compare the request body to the provided JSON string and ensure that the two are semantically
 // identical.
 // The order of keys in JSON objects is not important, but every value must be present.
 func MatchJSONFull(expected interface{}) MatchRule {
-	return MatchRuleFunc(func(r *http.Request) bool {
-		return matchJSON(r, expected) == jsondiff.FullMatch
+	return MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		match, description := matchJSON(r, expected)
+
+		return match == jsondiff.FullMatch, fmt.Sprintf("JSON body: expected full match, got %s\n%s", match, description)
 	})
 }
 
@@ -54,9 +53,10 @@ func MatchJSONFull(expected interface{}) MatchRule {
 //
 // As with MatchJSONFull, the order of keys is not important.
 func MatchJSONCompatible(expected interface{}) MatchRule {
-	return MatchRuleFunc(func(r *http.Request) bool {
-		match := matchJSON(r, expected)
+	return MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		match, description := matchJSON(r, expected)
 
-		return match == jsondiff.FullMatch || match == jsondiff.SupersetMatch
+		return match == jsondiff.FullMatch || match == jsondiff.SupersetMatch,
+			fmt.Sprintf("JSON body: expected compatible match, got %s\n%s", match, description)
 	})
 }
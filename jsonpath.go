@@ -0,0 +1,152 @@
+package gomockserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// errUnsupportedJSONPath is returned when a JSON path expression uses syntax that is not supported.
+var errUnsupportedJSONPath = errors.New("unsupported JSON path")
+
+// jsonPathStep is a single step in a JSON path expression, selecting either a named member of an object, an indexed
+// element of an array, or every child of an object or array.
+type jsonPathStep struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses a simple JSON path expression, made up of `$` followed by any number of `.name`, `['name']`,
+// `[index]`, `.*` or `[*]` steps. Filters, slices and recursive descent are not supported.
+func parseJSONPath(expression string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(expression, "$") {
+		return nil, fmt.Errorf("%w %q: must start with $", errUnsupportedJSONPath, expression)
+	}
+
+	steps := []jsonPathStep{}
+	rest := expression[1:]
+
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			return nil, fmt.Errorf("%w %q: recursive descent is not supported", errUnsupportedJSONPath, expression)
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf("%w %q: empty member name", errUnsupportedJSONPath, expression)
+			}
+
+			steps = append(steps, jsonPathStep{name: name, wildcard: name == "*"})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("%w %q: unterminated [", errUnsupportedJSONPath, expression)
+			}
+
+			step, err := parseJSONPathBracket(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("%w %q: %v", errUnsupportedJSONPath, expression, err)
+			}
+
+			steps = append(steps, step)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("%w %q: unexpected %q", errUnsupportedJSONPath, expression, rest)
+		}
+	}
+
+	return steps, nil
+}
+
+// parseJSONPathBracket parses the contents of a single `[...]` step of a JSON path expression.
+func parseJSONPathBracket(contents string) (jsonPathStep, error) {
+	if contents == "*" {
+		return jsonPathStep{wildcard: true}, nil
+	}
+
+	if len(contents) >= 2 && (contents[0] == '\'' || contents[0] == '"') && contents[len(contents)-1] == contents[0] {
+		return jsonPathStep{name: contents[1 : len(contents)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(contents)
+	if err != nil {
+		return jsonPathStep{}, fmt.Errorf("unsupported selector [%s]", contents)
+	}
+
+	return jsonPathStep{index: index, isIndex: true}, nil
+}
+
+// evaluateJSONPath finds every value in the document that is selected by the JSON path steps.
+func evaluateJSONPath(document interface{}, steps []jsonPathStep) []interface{} {
+	current := []interface{}{document}
+
+	for _, step := range steps {
+		next := []interface{}{}
+
+		for _, value := range current {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				if step.wildcard {
+					for _, key := range sortedJSONKeys(v) {
+						next = append(next, v[key])
+					}
+				} else if child, ok := v[step.name]; ok && !step.isIndex {
+					next = append(next, child)
+				}
+			case []interface{}:
+				switch {
+				case step.wildcard:
+					next = append(next, v...)
+				case step.isIndex:
+					index := step.index
+					if index < 0 {
+						index += len(v)
+					}
+
+					if index >= 0 && index < len(v) {
+						next = append(next, v[index])
+					}
+				}
+			}
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+// sortedJSONKeys returns the keys of the provided JSON object in sorted order.
+func sortedJSONKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// jsonPathString formats a value selected by a JSON path as a string, using the value itself for strings and the JSON
+// encoding of anything else.
+func jsonPathString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	encoded, _ := json.Marshal(value)
+
+	return string(encoded)
+}
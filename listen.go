@@ -0,0 +1,69 @@
+package gomockserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// WithAddress will start the mock server listening on the provided TCP address instead of a random port on
+// 127.0.0.1. This can be any address accepted by `net.Listen`, for example "127.0.0.1:8080" or "[::1]:0".
+func WithAddress(address string) Option {
+	return func(c *config) {
+		c.address = address
+	}
+}
+
+// WithListener will start the mock server using the provided listener instead of creating its own.
+// The listener will be closed when the server is closed. If this is a Unix domain socket listener then the server
+// behaves the same as with `WithUnixSocket`.
+func WithListener(listener net.Listener) Option {
+	return func(c *config) {
+		c.listener = listener
+	}
+}
+
+// WithUnixSocket will start the mock server listening on a Unix domain socket at the provided path.
+// Because a socket path can not be represented as the host of a URL, the server URL will be "http://localhost" and
+// `MockServer.Client` must be used to make requests, since it sends every request to the socket regardless of the host.
+// This can not be combined with TLS.
+func WithUnixSocket(path string) Option {
+	return func(c *config) {
+		c.unixSocket = path
+	}
+}
+
+// listen will create the listener to start the server with, or `nil` if the server should use its default listener.
+func (c config) listen() (net.Listener, error) {
+	switch {
+	case c.inProcess:
+		return newPipeListener(), nil
+	case c.listener != nil:
+		return c.listener, nil
+	case c.unixSocket != "":
+		return net.Listen("unix", c.unixSocket)
+	case c.address != "":
+		return net.Listen("tcp", c.address)
+	default:
+		return nil, nil
+	}
+}
+
+// unixSocketDialer builds a function that will always connect to the given Unix domain socket, regardless of the
+// address requested.
+func unixSocketDialer(path string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		var dialer net.Dialer
+
+		return dialer.DialContext(ctx, "unix", path)
+	}
+}
+
+// newUnixSocketClient builds an HTTP client that sends every request to the given Unix domain socket.
+func newUnixSocketClient(path string, http2 bool) *http.Client {
+	if http2 {
+		return &http.Client{Transport: newH2CTransport(unixSocketDialer(path))}
+	}
+
+	return &http.Client{Transport: &http.Transport{DialContext: unixSocketDialer(path)}}
+}
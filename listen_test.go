@@ -0,0 +1,140 @@
+package gomockserver_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/sazzer/gomockserver"
+)
+
+func TestServerAddress(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	is.NoErr(err)
+
+	address := listener.Addr().String()
+	is.NoErr(listener.Close())
+
+	server := gomockserver.New(t, gomockserver.WithAddress(address))
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPath("/testing"))
+
+	is.Equal(server.URL(), "http://"+address)
+
+	resp, err := http.Get(server.URL() + "/testing") //nolint:noctx
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+}
+
+func TestServerIPv6Address(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 is not available: %v", err)
+	}
+
+	is.NoErr(listener.Close())
+
+	server := gomockserver.New(t, gomockserver.WithAddress("[::1]:0"))
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPath("/testing"))
+
+	is.True(strings.HasPrefix(server.URL(), "http://[::1]:"))
+
+	resp, err := http.Get(server.URL() + "/testing") //nolint:noctx
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+}
+
+func TestServerListener(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	is.NoErr(err)
+
+	server := gomockserver.New(t, gomockserver.WithListener(listener))
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPath("/testing"))
+
+	is.Equal(server.URL(), "http://"+listener.Addr().String())
+
+	resp, err := http.Get(server.URL() + "/testing") //nolint:noctx
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+}
+
+func TestServerUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options []gomockserver.Option
+		proto   string
+	}{
+		{name: "HTTP/1.1", options: []gomockserver.Option{}, proto: "HTTP/1.1"},
+		{name: "h2c", options: []gomockserver.Option{gomockserver.WithHTTP2()}, proto: "HTTP/2.0"},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			// Unix socket paths are limited in length, so this avoids the long directory names from `t.TempDir`.
+			dir, err := ioutil.TempDir("", "gomockserver")
+			is.NoErr(err)
+
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "mock.sock")
+
+			server := gomockserver.New(t, append(tt.options, gomockserver.WithUnixSocket(path))...)
+			defer server.Close()
+
+			server.Matches(gomockserver.MatchURLPath("/testing"), gomockserver.MatchProto(tt.proto)).
+				RespondsWith(gomockserver.ResponseBody([]byte("Hello")))
+
+			is.Equal(server.URL(), "http://localhost")
+
+			resp, err := server.Client().Get(server.URL() + "/testing") //nolint:noctx
+			is.NoErr(err)
+
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			is.NoErr(err)
+
+			is.Equal(resp.StatusCode, http.StatusOK)
+			is.Equal(string(body), "Hello")
+
+			conn, err := net.Dial("unix", path)
+			is.NoErr(err)
+			is.NoErr(conn.Close())
+		})
+	}
+}
@@ -1,17 +1,58 @@
 package gomockserver
 
+import (
+	"crypto/x509"
+	"io/fs"
+	"net/http"
+)
+
 // MockServer represents the actual server that will be used in the tests.
 type MockServer interface {
-	// Close will shut the mock server down. This must always be called, preferably via `defer`.
+	// Close will shut the mock server down. This is done automatically when the test finishes, but can be called
+	// sooner if needed.
 	Close()
+	// Verify will close the mock server, and then report a summary of every expectation that was not met and every
+	// request that was not matched. This is done automatically when the test finishes if the server was created by
+	// `New`.
+	Verify()
 	// URL will generate a URL representing the mock server. This includes the scheme, host and post of the server.
 	URL() string
+	// Client will return an HTTP client that is configured to make requests to the mock server. If the server is using
+	// TLS then this client will trust its certificate.
+	Client() *http.Client
+	// Transport will return the `http.RoundTripper` used by the client from `Client`, so that it can be used to
+	// configure other clients to make requests to the mock server.
+	Transport() http.RoundTripper
+	// CertPool will return a certificate pool containing the certificate of the mock server, if it is using TLS.
+	CertPool() *x509.CertPool
 	// Matches will record a new match against the server that will potentially process any incoming requests.
 	Matches(...MatchRule) *Match
 	// Mount will create a new
 	Mount(Mock) *Match
+	// LoadMocks will load every mock from the provided file or directory of declarative mock definitions, as
+	// described by `LoadMocks`, and mount them on the server.
+	LoadMocks(path string) ([]*Match, error)
+	// MountOpenAPI will load the OpenAPI 3 document from the provided JSON or YAML file, and mount a match for every
+	// operation in it that responds with the example or schema of its lowest successful response.
+	MountOpenAPI(path string, opts ...OpenAPIOption) ([]*Match, error)
+	// MountDirectory will serve every file in the filesystem as a static site under the provided URL path prefix,
+	// using `index.html` for directories. Files are served in the same way as `ResponseFS`.
+	MountDirectory(prefix string, fsys fs.FS) *Match
+	// WebSocket will create a new WebSocket endpoint on the server, accepting upgrade requests that match the rules.
+	WebSocket(...MatchRule) *WebSocketMock
 	// UnmatchedCount will return the number of times a request has been handmed and not matched.
 	UnmatchedCount() int
+	// Requests will return every request that the server has received, in the order they were received.
+	Requests() RecordedRequests
+	// UnmatchedRequests will return every request that the server has received that was not matched.
+	UnmatchedRequests() RecordedRequests
+	// ScenarioState will return the current state of the named scenario.
+	ScenarioState(name string) string
+	// SetScenarioState will move the named scenario to the given state.
+	SetScenarioState(name, state string)
+	// ResetScenarios will return the named scenarios to the `ScenarioStarted` state, or every scenario if none are
+	// named.
+	ResetScenarios(names ...string)
 }
 
 // Mock represents a lightweight representation of a mock to add to the server.
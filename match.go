@@ -1,12 +1,24 @@
 package gomockserver
 
-import "net/http"
+import (
+	"net/http"
+	"sync"
+)
 
 // Match represents a matching in the mock server to potentially handle incoming requests.
 type Match struct {
-	rules     MatchRules
-	responses ResponseBuilders
-	count     int
+	mutex         sync.Mutex
+	rules         MatchRules
+	responses     ResponseBuilders
+	count         int
+	requests      RecordedRequests
+	expectation   *expectation
+	sequence      []ResponseBuilder
+	sequenceMode  SequenceMode
+	scenarios     *scenarios
+	scenario      string
+	requiredState string
+	nextState     string
 }
 
 // Matches will check if every rule in this `Match` passes for the incoming request.
@@ -14,8 +26,65 @@ func (m *Match) Matches(r *http.Request) bool {
 	return m.rules.Matches(r)
 }
 
+// explain will check every rule in this `Match` against the incoming request, and describe the result of each one.
+func (m *Match) explain(r *http.Request) []ruleResult {
+	rules := flattenRules(m.rules)
+	results := make([]ruleResult, 0, len(rules))
+
+	for _, rule := range rules {
+		rewindBody(r)
+
+		matched, reason := explainRule(rule, r)
+		results = append(results, ruleResult{matched: matched, reason: reason})
+	}
+
+	if result, ok := m.explainSequence(); ok {
+		results = append(results, result)
+	}
+
+	if result, ok := m.explainScenario(); ok {
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// handled will record that this match has been used to respond to the provided request, returning the response
+// builders to use for the response. If this match can no longer respond to requests then this returns `false`
+// instead.
+func (m *Match) handled(request RecordedRequest) (ResponseBuilders, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.scenario != "" {
+		m.scenarios.mutex.Lock()
+		defer m.scenarios.mutex.Unlock()
+	}
+
+	if m.exhausted() || !m.inRequiredState() {
+		return nil, false
+	}
+
+	if m.scenario != "" && m.nextState != "" {
+		m.scenarios.states[m.scenario] = m.nextState
+	}
+
+	responses := append(ResponseBuilders{}, m.responses...)
+	if next := m.nextInSequence(); next != nil {
+		responses = append(responses, next)
+	}
+
+	m.count++
+	m.requests = append(m.requests, request)
+
+	return responses, true
+}
+
 // RespondsWith registers new response builders to use to build the response to an incoming request.
 func (m *Match) RespondsWith(builders ...ResponseBuilder) *Match {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	m.responses = append(m.responses, ResponseBuilders(builders))
 
 	return m
@@ -23,5 +92,16 @@ func (m *Match) RespondsWith(builders ...ResponseBuilder) *Match {
 
 // Count will return the number of times this match has been used to respond to a request.
 func (m *Match) Count() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	return m.count
 }
+
+// Requests will return every request that this match has been used to respond to, in the order they were received.
+func (m *Match) Requests() RecordedRequests {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append(RecordedRequests{}, m.requests...)
+}
@@ -1,8 +1,11 @@
 package gomockserver
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strings"
 )
 
 // MatchRule represents a rule to match against to see if a request should be processed.
@@ -25,6 +28,8 @@ type MatchRules []MatchRule
 
 func (m MatchRules) Matches(r *http.Request) bool {
 	for _, match := range m {
+		rewindBody(r)
+
 		if !match.Matches(r) {
 			return false
 		}
@@ -33,15 +38,69 @@ func (m MatchRules) Matches(r *http.Request) bool {
 	return true
 }
 
-func matchURL(matcher func(url.URL) bool) MatchRule {
-	return MatchRuleFunc(func(r *http.Request) bool {
-		uri, err := url.ParseRequestURI(r.RequestURI)
-		if err != nil {
-			return false
+func (m MatchRules) Explain(r *http.Request) (bool, string) {
+	passed, reasons := explainRules(r, m)
+
+	return passed == len(m), fmt.Sprintf("all of (%s)", strings.Join(reasons, "; "))
+}
+
+// MatchAll builds a `MatchRule` that passes only if every one of the provided rules passes.
+// This is equivalent to using `MatchRules`, and is provided for clarity when combined with `MatchAny` and `MatchNot`.
+func MatchAll(rules ...MatchRule) MatchRule {
+	return MatchRules(rules)
+}
+
+// anyRules is a `MatchRule` that passes if any one of a slice of `MatchRule` passes.
+type anyRules []MatchRule
+
+func (m anyRules) Matches(r *http.Request) bool {
+	for _, rule := range m {
+		rewindBody(r)
+
+		restore := savePathParams(r)
+
+		if rule.Matches(r) {
+			return true
 		}
 
-		if uri == nil {
-			return false
+		restore()
+	}
+
+	return false
+}
+
+func (m anyRules) Explain(r *http.Request) (bool, string) {
+	passed, reasons := explainRules(r, m)
+
+	return passed > 0, fmt.Sprintf("any of (%s)", strings.Join(reasons, "; "))
+}
+
+// MatchAny builds a `MatchRule` that passes if at least one of the provided rules passes.
+// The rules are checked in order, and no more are checked once one has passed.
+func MatchAny(rules ...MatchRule) MatchRule {
+	return anyRules(rules)
+}
+
+// MatchNot builds a `MatchRule` that passes only if the provided rule does not pass.
+// Any path parameters captured by the provided rule are discarded.
+func MatchNot(rule MatchRule) MatchRule {
+	return MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		rewindBody(r)
+
+		restore := savePathParams(r)
+		defer restore()
+
+		matched, reason := explainRule(rule, r)
+
+		return !matched, fmt.Sprintf("not (%s)", reason)
+	})
+}
+
+func matchURL(matcher func(url.URL) (bool, string)) MatchRule {
+	return MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		uri, err := url.ParseRequestURI(r.RequestURI)
+		if err != nil {
+			return false, fmt.Sprintf("URL: unable to parse %s: %v", r.RequestURI, err)
 		}
 
 		return matcher(*uri)
@@ -51,15 +110,49 @@ func matchURL(matcher func(url.URL) bool) MatchRule {
 // MatchURLPath builds a `MatchRule` to check if the URL Path of the request matches the one provided.
 // Note that this does a complete match, not a partial one.
 func MatchURLPath(expected string) MatchRule {
-	return matchURL(func(uri url.URL) bool {
-		return uri.EscapedPath() == expected
+	return matchURL(func(uri url.URL) (bool, string) {
+		return uri.EscapedPath() == expected, fmt.Sprintf("URL path: expected %s, got %s", expected, uri.EscapedPath())
+	})
+}
+
+// MatchURLPathRegex builds a `MatchRule` to check if the URL Path of the request matches the provided regular
+// expression. As with `regexp.MatchString`, the pattern can match any part of the path unless it is anchored with `^`
+// and `$`.
+// This will panic if the pattern is not a valid regular expression.
+func MatchURLPathRegex(pattern string) MatchRule {
+	re := regexp.MustCompile(pattern)
+
+	return matchURL(func(uri url.URL) (bool, string) {
+		return re.MatchString(uri.EscapedPath()),
+			fmt.Sprintf("URL path: expected to match regex %s, got %s", pattern, uri.EscapedPath())
+	})
+}
+
+// MatchURLPathGlob builds a `MatchRule` to check if the URL Path of the request matches the provided glob pattern.
+// Within the pattern, `*` matches any sequence of characters within a single path segment, `**` matches any sequence
+// of characters across segments, and `?` matches any single character other than `/`. Unlike with
+// `MatchURLPathRegex`, the glob must match the entire path.
+func MatchURLPathGlob(pattern string) MatchRule {
+	re := compileGlob(pattern, true)
+
+	return matchURL(func(uri url.URL) (bool, string) {
+		return re.MatchString(uri.EscapedPath()),
+			fmt.Sprintf("URL path: expected to match glob %s, got %s", pattern, uri.EscapedPath())
 	})
 }
 
 // MatchMethod builds a `MatchRule` to check if the HTTP Method of the request matches the one provided.
 func MatchMethod(method string) MatchRule {
-	return MatchRuleFunc(func(r *http.Request) bool {
-		return r.Method == method
+	return MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		return r.Method == method, fmt.Sprintf("method: expected %s, got %s", method, r.Method)
+	})
+}
+
+// MatchProto builds a `MatchRule` to check if the request was made using the protocol version provided, for example
+// "HTTP/1.1" or "HTTP/2.0".
+func MatchProto(proto string) MatchRule {
+	return MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		return r.Proto == proto, fmt.Sprintf("protocol: expected %s, got %s", proto, r.Proto)
 	})
 }
 
@@ -71,36 +164,170 @@ func MatchRequest(method, url string) MatchRule {
 	}
 }
 
+// matchHeader builds a `MatchRule` that checks the values of the named header, using the expected description when
+// explaining the result.
+func matchHeader(name, expected string, matcher func(values []string) bool) MatchRule {
+	return MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		values := r.Header.Values(name)
+
+		return matcher(values), fmt.Sprintf("header %s: expected %s, got %v", name, expected, values)
+	})
+}
+
+// matchURLQuery builds a `MatchRule` that checks the values of the named query parameter, using the expected
+// description when explaining the result.
+func matchURLQuery(name, expected string, matcher func(values []string) bool) MatchRule {
+	return matchURL(func(uri url.URL) (bool, string) {
+		values := uri.Query()[name]
+
+		return matcher(values), fmt.Sprintf("query parameter %s: expected %s, got %v", name, expected, values)
+	})
+}
+
+// anyValue will check if any of the provided values passes the given check.
+func anyValue(values []string, check func(string) bool) bool {
+	for _, v := range values {
+		if check(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // MatchHeader builds a `MatchRule` to check if the given header is present and has the given value.
 // If the header is repeated then only one of the repeated values needs to have the provided value.
 func MatchHeader(name, value string) MatchRule {
-	return MatchRuleFunc(func(r *http.Request) bool {
-		values := r.Header.Values(name)
+	return matchHeader(name, value, func(values []string) bool {
+		return anyValue(values, func(v string) bool {
+			return v == value
+		})
+	})
+}
 
-		for _, v := range values {
-			if v == value {
-				return true
-			}
-		}
+// MatchHeaderRegex builds a `MatchRule` to check if the given header is present and has a value matching the provided
+// regular expression. If the header is repeated then only one of the repeated values needs to match.
+// As with `regexp.MatchString`, the pattern can match any part of the value unless it is anchored with `^` and `$`.
+// This will panic if the pattern is not a valid regular expression.
+func MatchHeaderRegex(name, pattern string) MatchRule {
+	re := regexp.MustCompile(pattern)
+
+	return matchHeader(name, "to match regex "+pattern, func(values []string) bool {
+		return anyValue(values, re.MatchString)
+	})
+}
+
+// MatchHeaderGlob builds a `MatchRule` to check if the given header is present and has a value matching the provided
+// glob pattern. If the header is repeated then only one of the repeated values needs to match.
+// Within the pattern, `*` matches any sequence of characters and `?` matches any single character.
+func MatchHeaderGlob(name, pattern string) MatchRule {
+	re := compileGlob(pattern, false)
+
+	return matchHeader(name, "to match glob "+pattern, func(values []string) bool {
+		return anyValue(values, re.MatchString)
+	})
+}
 
-		return false
+// MatchHeaderPresent builds a `MatchRule` to check if the given header is present, regardless of its value.
+func MatchHeaderPresent(name string) MatchRule {
+	return matchHeader(name, "any value", func(values []string) bool {
+		return len(values) > 0
+	})
+}
+
+// MatchHeaderAbsent builds a `MatchRule` to check if the given header is not present at all.
+func MatchHeaderAbsent(name string) MatchRule {
+	return matchHeader(name, "no value", func(values []string) bool {
+		return len(values) == 0
 	})
 }
 
 // MatchURLQuery builds a `MatchRule` to check if a query parameter is present with the given name and value.
 // If the query parameter is repeated then only one of the repeated values needs to have the provided value.
 func MatchURLQuery(name, value string) MatchRule {
-	return matchURL(func(uri url.URL) bool {
-		for n, values := range uri.Query() {
-			if name == n {
-				for _, v := range values {
-					if value == v {
-						return true
-					}
-				}
-			}
-		}
+	return matchURLQuery(name, value, func(values []string) bool {
+		return anyValue(values, func(v string) bool {
+			return v == value
+		})
+	})
+}
 
-		return false
+// MatchURLQueryRegex builds a `MatchRule` to check if a query parameter is present with the given name and a value
+// matching the provided regular expression. If the query parameter is repeated then only one of the repeated values
+// needs to match.
+// As with `regexp.MatchString`, the pattern can match any part of the value unless it is anchored with `^` and `$`.
+// This will panic if the pattern is not a valid regular expression.
+func MatchURLQueryRegex(name, pattern string) MatchRule {
+	re := regexp.MustCompile(pattern)
+
+	return matchURLQuery(name, "to match regex "+pattern, func(values []string) bool {
+		return anyValue(values, re.MatchString)
+	})
+}
+
+// MatchURLQueryGlob builds a `MatchRule` to check if a query parameter is present with the given name and a value
+// matching the provided glob pattern. If the query parameter is repeated then only one of the repeated values needs to
+// match.
+// Within the pattern, `*` matches any sequence of characters and `?` matches any single character.
+func MatchURLQueryGlob(name, pattern string) MatchRule {
+	re := compileGlob(pattern, false)
+
+	return matchURLQuery(name, "to match glob "+pattern, func(values []string) bool {
+		return anyValue(values, re.MatchString)
+	})
+}
+
+// MatchURLQueryPresent builds a `MatchRule` to check if a query parameter is present with the given name, regardless
+// of its value.
+func MatchURLQueryPresent(name string) MatchRule {
+	return matchURLQuery(name, "any value", func(values []string) bool {
+		return len(values) > 0
+	})
+}
+
+// MatchURLQueryAbsent builds a `MatchRule` to check if there is no query parameter with the given name.
+func MatchURLQueryAbsent(name string) MatchRule {
+	return matchURLQuery(name, "no value", func(values []string) bool {
+		return len(values) == 0
 	})
 }
+
+// compileGlob will convert a glob pattern into an equivalent regular expression that matches the entire input.
+// If `paths` is true then `*` and `?` will not match a `/`, and `**` can be used to match across path segments.
+// Any character can be escaped with a `\` to match it literally.
+func compileGlob(pattern string, paths bool) *regexp.Regexp {
+	many := ".*"
+	single := "."
+
+	if paths {
+		many = "[^/]*"
+		single = "[^/]"
+	}
+
+	var b strings.Builder
+
+	b.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '*' && paths && i+1 < len(pattern) && pattern[i+1] == '*':
+			b.WriteString(".*")
+
+			i++
+		case c == '*':
+			b.WriteString(many)
+		case c == '?':
+			b.WriteString(single)
+		case c == '\\' && i+1 < len(pattern):
+			b.WriteString(regexp.QuoteMeta(pattern[i+1 : i+2]))
+
+			i++
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
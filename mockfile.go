@@ -0,0 +1,368 @@
+package gomockserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MockFileError describes a problem with a file of declarative mock definitions.
+type MockFileError struct {
+	// File is the path to the file that has the problem.
+	File string
+	// Line is the line within the file that has the problem, or zero if it does not apply to a single line.
+	Line int
+	// Message describes the problem.
+	Message string
+}
+
+func (e *MockFileError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+// mockFile is the structure of a file of declarative mock definitions.
+type mockFile struct {
+	Mocks []mockDefinition `yaml:"mocks"`
+}
+
+// mockDefinition is the declarative definition of a single mock.
+type mockDefinition struct {
+	Request  mockRequest  `yaml:"request"`
+	Response mockResponse `yaml:"response"`
+}
+
+// mockRequest is the declarative definition of the requests that a mock matches.
+type mockRequest struct {
+	Method   string            `yaml:"method"`
+	Path     string            `yaml:"path"`
+	Headers  map[string]string `yaml:"headers"`
+	Query    map[string]string `yaml:"query"`
+	JSON     interface{}       `yaml:"json"`
+	JSONFull interface{}       `yaml:"jsonFull"`
+}
+
+// mockResponse is the declarative definition of the response that a mock sends.
+type mockResponse struct {
+	Status   int               `yaml:"status"`
+	Headers  map[string]string `yaml:"headers"`
+	Body     *string           `yaml:"body"`
+	JSON     interface{}       `yaml:"json"`
+	BodyFile string            `yaml:"bodyFile"`
+}
+
+// yamlErrorLine extracts the line number from the errors produced by the YAML decoder.
+var yamlErrorLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// LoadMocks will load the declarative mock definitions from the provided JSON or YAML file. If the path is a directory
+// then every file in it with a `.json`, `.yaml` or `.yml` extension is loaded, in name order.
+//
+// Each file contains a list of mocks, each of which describes the requests to match and the response to send:
+//
+//	mocks:
+//	  - request:
+//	      method: POST
+//	      path: /users/{id}/orders
+//	      headers:
+//	        Authorization: Bearer abc
+//	      query:
+//	        dryRun: "true"
+//	      json:
+//	        item: widget
+//	    response:
+//	      status: 201
+//	      headers:
+//	        Location: /orders/1
+//	      json:
+//	        id: 1
+//
+// The request path can be any template supported by `MatchURLPathTemplate`, and the request body is matched using
+// `MatchJSONCompatible` for `json` or `MatchJSONFull` for `jsonFull`. The response body can be given as a string with
+// `body`, as a value to encode with `json`, or as a path to a file with `bodyFile`, which is relative to the file
+// containing the definition. Any problems with the definitions are returned as a `*MockFileError`.
+func LoadMocks(path string) ([]Mock, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return loadMockFile(path)
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mocks := []Mock{}
+
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+
+		if entry.IsDir() {
+			continue
+		}
+
+		loaded, err := loadMockFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mocks = append(mocks, loaded...)
+	}
+
+	return mocks, nil
+}
+
+// loadMockFile will load the declarative mock definitions from a single JSON or YAML file.
+func loadMockFile(path string) ([]Mock, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file mockFile
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&file); err != nil {
+		if errors.Is(err, io.EOF) {
+			return []Mock{}, nil
+		}
+
+		return nil, newYAMLError(path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, newYAMLError(path, err)
+	}
+
+	mocks := make([]Mock, 0, len(file.Mocks))
+
+	for i, definition := range file.Mocks {
+		mock, err := definition.build(filepath.Dir(path), func(keys ...interface{}) int {
+			return nodeLine(&root, append([]interface{}{"mocks", i}, keys...)...)
+		})
+		if err != nil {
+			err.File = path
+
+			return nil, err
+		}
+
+		mocks = append(mocks, mock)
+	}
+
+	return mocks, nil
+}
+
+// newYAMLError converts an error from the YAML decoder into a `*MockFileError`.
+func newYAMLError(path string, err error) *MockFileError {
+	message := err.Error()
+
+	var typeError *yaml.TypeError
+	if errors.As(err, &typeError) && len(typeError.Errors) > 0 {
+		message = typeError.Errors[0]
+	}
+
+	if parts := yamlErrorLine.FindStringSubmatch(message); parts != nil {
+		line, _ := strconv.Atoi(parts[1])
+
+		return &MockFileError{File: path, Line: line, Message: parts[2]}
+	}
+
+	return &MockFileError{File: path, Message: message}
+}
+
+// nodeLine finds the line number of the YAML node found by following the provided mapping keys and sequence indexes
+// from the root node. If the full path does not exist then the line of the deepest node that does is returned.
+func nodeLine(root *yaml.Node, keys ...interface{}) int {
+	node := root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	for _, key := range keys {
+		next := childNode(node, key)
+		if next == nil {
+			break
+		}
+
+		node = next
+	}
+
+	return node.Line
+}
+
+// childNode finds the child of the provided node with the given mapping key or sequence index, or `nil` if there is no
+// such child.
+func childNode(node *yaml.Node, key interface{}) *yaml.Node {
+	switch k := key.(type) {
+	case string:
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == k {
+				return node.Content[i+1]
+			}
+		}
+	case int:
+		if node.Kind == yaml.SequenceNode && k < len(node.Content) {
+			return node.Content[k]
+		}
+	}
+
+	return nil
+}
+
+// build converts the mock definition into a `Mock`, resolving any files relative to the provided directory and using
+// the provided function to find the line numbers to report problems at.
+func (d mockDefinition) build(dir string, line func(keys ...interface{}) int) (Mock, *MockFileError) {
+	mock := Mock{}
+
+	if d.Request.Method != "" {
+		mock.Matches = append(mock.Matches, MatchMethod(d.Request.Method))
+	}
+
+	if d.Request.Path != "" {
+		if _, _, err := parsePathTemplate(d.Request.Path); err != nil {
+			return Mock{}, &MockFileError{Line: line("request", "path"), Message: err.Error()}
+		}
+
+		mock.Matches = append(mock.Matches, MatchURLPathTemplate(d.Request.Path))
+	}
+
+	for _, name := range sortedKeys(d.Request.Headers) {
+		mock.Matches = append(mock.Matches, MatchHeader(name, d.Request.Headers[name]))
+	}
+
+	for _, name := range sortedKeys(d.Request.Query) {
+		mock.Matches = append(mock.Matches, MatchURLQuery(name, d.Request.Query[name]))
+	}
+
+	if d.Request.JSON != nil && d.Request.JSONFull != nil {
+		return Mock{}, &MockFileError{
+			Line:    line("request", "jsonFull"),
+			Message: "only one of json and jsonFull can be used",
+		}
+	}
+
+	if d.Request.JSON != nil {
+		mock.Matches = append(mock.Matches, MatchJSONCompatible(d.Request.JSON))
+	}
+
+	if d.Request.JSONFull != nil {
+		mock.Matches = append(mock.Matches, MatchJSONFull(d.Request.JSONFull))
+	}
+
+	response, err := d.Response.build(dir, func(keys ...interface{}) int {
+		return line(append([]interface{}{"response"}, keys...)...)
+	})
+	if err != nil {
+		return Mock{}, err
+	}
+
+	mock.Response = response
+
+	return mock, nil
+}
+
+// build converts the response definition into the `ResponseBuilder`s to use for the response.
+func (r mockResponse) build(dir string, line func(keys ...interface{}) int) ([]ResponseBuilder, *MockFileError) {
+	builders := []ResponseBuilder{}
+	bodies := 0
+
+	if r.Body != nil {
+		bodies++
+
+		builders = append(builders, ResponseBody([]byte(*r.Body)))
+	}
+
+	if r.JSON != nil {
+		bodies++
+
+		builders = append(builders, ResponseJSON(r.JSON))
+	}
+
+	if r.BodyFile != "" {
+		bodies++
+
+		path := r.BodyFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			return nil, &MockFileError{
+				Line:    line("bodyFile"),
+				Message: fmt.Sprintf("body file %s does not exist", path),
+			}
+		}
+
+		builders = append(builders, ResponseFile(path))
+	}
+
+	if bodies > 1 {
+		return nil, &MockFileError{Line: line(), Message: "only one of body, json and bodyFile can be used"}
+	}
+
+	if r.Status != 0 {
+		if r.Status < 100 || r.Status > 599 {
+			return nil, &MockFileError{Line: line("status"), Message: fmt.Sprintf("invalid status code %d", r.Status)}
+		}
+
+		builders = append(builders, ResponseStatus(r.Status))
+	}
+
+	for _, name := range sortedKeys(r.Headers) {
+		builders = append(builders, ResponseSetHeader(name, r.Headers[name]))
+	}
+
+	return builders, nil
+}
+
+// sortedKeys returns the keys of the provided map in sorted order.
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (s *server) LoadMocks(path string) ([]*Match, error) {
+	mocks, err := LoadMocks(path)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*Match, 0, len(mocks))
+	for _, mock := range mocks {
+		matches = append(matches, s.Mount(mock))
+	}
+
+	return matches, nil
+}
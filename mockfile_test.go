@@ -0,0 +1,140 @@
+package gomockserver_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/sazzer/gomockserver"
+)
+
+func TestLoadMocksDirectory(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
+	defer server.Close()
+
+	matches, err := server.LoadMocks("testdata/mocks")
+	is.NoErr(err)
+	is.Equal(len(matches), 3)
+
+	tests := []struct {
+		method  string
+		url     string
+		headers map[string]string
+		body    string
+		status  int
+		header  string
+		value   string
+		output  string
+	}{
+		{method: http.MethodGet, url: "/orders?page=2", status: http.StatusOK, header: "Content-Type",
+			value: "text/plain", output: "Page 2"},
+		{method: http.MethodGet, url: "/orders?page=3", status: http.StatusNotFound},
+		{method: http.MethodGet, url: "/users/1", headers: map[string]string{"Authorization": "Bearer abc"},
+			status: http.StatusOK, header: "X-Source", value: "file", output: "{\"id\":1,\"name\":\"Test\"}\n"},
+		{method: http.MethodGet, url: "/users/1", status: http.StatusNotFound},
+		{method: http.MethodPost, url: "/users", body: `{"name":"Test","extra":true}`, status: http.StatusCreated,
+			header: "Location", value: "/users/2", output: `{"id":2,"name":"Test"}`},
+		{method: http.MethodPost, url: "/users", body: `{"name":"Other"}`, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequestWithContext(context.Background(), tt.method, server.URL()+tt.url,
+			strings.NewReader(tt.body))
+		is.NoErr(err)
+
+		for name, value := range tt.headers {
+			req.Header.Set(name, value)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		is.NoErr(err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		is.NoErr(err)
+		resp.Body.Close()
+
+		is.Equal(resp.StatusCode, tt.status)
+
+		if tt.header != "" {
+			is.Equal(resp.Header.Get(tt.header), tt.value)
+			is.Equal(string(body), tt.output)
+		}
+	}
+
+	is.Equal(matches[0].Count(), 1)
+	is.Equal(matches[1].Count(), 1)
+	is.Equal(matches[2].Count(), 1)
+}
+
+func TestLoadMocksFile(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	mocks, err := gomockserver.LoadMocks("testdata/mocks/users.yaml")
+	is.NoErr(err)
+	is.Equal(len(mocks), 2)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Mount(mocks[1])
+
+	resp, err := http.Post(server.URL()+"/users", "application/json", //nolint:noctx
+		strings.NewReader(`{"name":"Test"}`))
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusCreated)
+	is.Equal(resp.Header.Get("Content-Type"), "application/json")
+}
+
+func TestLoadMocksErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		file    string
+		line    int
+		message string
+	}{
+		{file: "unknown-field.yaml", line: 4, message: "field paht not found in type gomockserver.mockRequest"},
+		{file: "unknown-field.json", line: 5, message: "field stauts not found in type gomockserver.mockResponse"},
+		{file: "wrong-type.yaml", line: 3, message: "cannot unmarshal !!str `OK` into int"},
+		{file: "syntax.yaml", line: 3, message: "found unexpected end of stream"},
+		{file: "path-template.yaml", line: 6,
+			message: `wildcard must be the final segment of path template "/users/*/orders"`},
+		{file: "multiple-bodies.yaml", line: 3, message: "only one of body, json and bodyFile can be used"},
+		{file: "missing-body-file.yaml", line: 4,
+			message: "body file testdata/invalid-mocks/missing.json does not exist"},
+		{file: "status.yaml", line: 3, message: "invalid status code 1000"},
+		{file: "json-and-json-full.yaml", line: 6, message: "only one of json and jsonFull can be used"},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.file, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			path := "testdata/invalid-mocks/" + tt.file
+
+			_, err := gomockserver.LoadMocks(path)
+			is.True(err != nil)
+			is.Equal(err.Error(), fmt.Sprintf("%s:%d: %s", path, tt.line, tt.message))
+
+			var fileError *gomockserver.MockFileError
+			is.True(errors.As(err, &fileError))
+			is.Equal(fileError.File, path)
+			is.Equal(fileError.Line, tt.line)
+		})
+	}
+}
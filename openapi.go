@@ -0,0 +1,545 @@
+package gomockserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedOpenAPIVersion is returned when loading an OpenAPI document that is not OpenAPI 3.
+var ErrUnsupportedOpenAPIVersion = errors.New("only OpenAPI 3 documents are supported")
+
+// openAPIMethods are the operations that can be defined on an OpenAPI path, in the order they are mounted.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openAPIIgnoredHeaders are the header parameters that the OpenAPI specification says must be ignored, since they are
+// described elsewhere in the document.
+var openAPIIgnoredHeaders = map[string]bool{"Accept": true, "Content-Type": true, "Authorization": true}
+
+// openAPIConfig represents the configuration for mounting an OpenAPI document that is built up from the provided
+// options.
+type openAPIConfig struct {
+	validate bool
+}
+
+// OpenAPIOption represents a configuration option to apply when mounting an OpenAPI document.
+type OpenAPIOption func(*openAPIConfig)
+
+// OpenAPIValidateRequests will check that every request to an operation conforms to the parameters and request body
+// schema in the document, reporting any violations as failures of the test.
+func OpenAPIValidateRequests() OpenAPIOption {
+	return func(c *openAPIConfig) {
+		c.validate = true
+	}
+}
+
+// openAPIDocument is a parsed OpenAPI document.
+type openAPIDocument struct {
+	root map[string]interface{}
+}
+
+// openAPIOperation is a single operation from an OpenAPI document.
+type openAPIOperation struct {
+	document    *openAPIDocument
+	method      string
+	path        string
+	parameters  []map[string]interface{}
+	requestBody map[string]interface{}
+	responses   map[string]interface{}
+}
+
+// loadOpenAPI will load the OpenAPI document, in either JSON or YAML, from the provided file.
+func loadOpenAPI(path string) (*openAPIDocument, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	root, _ := stringKeys(decoded).(map[string]interface{})
+
+	if version, _ := root["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("%s: %w", path, ErrUnsupportedOpenAPIVersion)
+	}
+
+	return &openAPIDocument{root: root}, nil
+}
+
+// stringKeys converts every object in the decoded YAML value to have string keys. YAML allows keys of any type, so
+// unquoted keys such as the status codes of responses would otherwise be decoded as numbers.
+func stringKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = stringKeys(item)
+		}
+
+		return v
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[fmt.Sprint(key)] = stringKeys(item)
+		}
+
+		return object
+	case []interface{}:
+		for i, item := range v {
+			v[i] = stringKeys(item)
+		}
+
+		return v
+	}
+
+	return value
+}
+
+// resolve follows any local `$ref` in the provided value, returning the object that it refers to. If the value is not
+// an object then an empty object is returned.
+func (d *openAPIDocument) resolve(value interface{}) map[string]interface{} {
+	for i := 0; i < 32; i++ {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{}
+		}
+
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object
+		}
+
+		value = d.lookup(ref)
+	}
+
+	return map[string]interface{}{}
+}
+
+// lookup finds the value in the document referred to by a local reference such as `#/components/schemas/Pet`.
+func (d *openAPIDocument) lookup(ref string) interface{} {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+
+	var current interface{} = d.root
+
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		current = object[part]
+	}
+
+	return current
+}
+
+// basePath returns the path of the first server in the document, which every operation path is relative to.
+func (d *openAPIDocument) basePath() string {
+	servers, _ := d.root["servers"].([]interface{})
+	if len(servers) == 0 {
+		return ""
+	}
+
+	server, _ := servers[0].(map[string]interface{})
+	serverURL, _ := server["url"].(string)
+
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(parsed.Path, "/")
+}
+
+// operations returns every operation in the document. Paths with fewer path parameters are returned first, so that
+// literal paths such as `/pets/mine` take priority over templated ones such as `/pets/{petId}`.
+func (d *openAPIDocument) operations() []openAPIOperation {
+	paths, _ := d.root["paths"].(map[string]interface{})
+
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		pi, pj := strings.Count(names[i], "{"), strings.Count(names[j], "{")
+		if pi != pj {
+			return pi < pj
+		}
+
+		return names[i] < names[j]
+	})
+
+	operations := []openAPIOperation{}
+
+	for _, name := range names {
+		item := d.resolve(paths[name])
+		shared := d.parameters(item["parameters"])
+
+		for _, method := range openAPIMethods {
+			if _, ok := item[method]; !ok {
+				continue
+			}
+
+			definition := d.resolve(item[method])
+
+			operations = append(operations, openAPIOperation{
+				document:    d,
+				method:      strings.ToUpper(method),
+				path:        name,
+				parameters:  mergeOpenAPIParameters(shared, d.parameters(definition["parameters"])),
+				requestBody: d.resolve(definition["requestBody"]),
+				responses:   d.resolve(definition["responses"]),
+			})
+		}
+	}
+
+	return operations
+}
+
+// parameters resolves every parameter in the provided list of parameter definitions.
+func (d *openAPIDocument) parameters(value interface{}) []map[string]interface{} {
+	list, _ := value.([]interface{})
+	parameters := make([]map[string]interface{}, 0, len(list))
+
+	for _, parameter := range list {
+		parameters = append(parameters, d.resolve(parameter))
+	}
+
+	return parameters
+}
+
+// mergeOpenAPIParameters combines the parameters shared by every operation on a path with those of a single operation,
+// with the operation parameters replacing any shared ones with the same name and location.
+func mergeOpenAPIParameters(shared, operation []map[string]interface{}) []map[string]interface{} {
+	key := func(parameter map[string]interface{}) string {
+		return fmt.Sprintf("%v:%v", parameter["in"], parameter["name"])
+	}
+
+	overridden := map[string]bool{}
+	for _, parameter := range operation {
+		overridden[key(parameter)] = true
+	}
+
+	merged := []map[string]interface{}{}
+
+	for _, parameter := range shared {
+		if !overridden[key(parameter)] {
+			merged = append(merged, parameter)
+		}
+	}
+
+	return append(merged, operation...)
+}
+
+// response builds the response to send for the operation, using the lowest successful status code it defines.
+func (o openAPIOperation) response() ResponseBuilder {
+	status, definition := o.responseDefinition()
+	builders := ResponseBuilders{ResponseStatus(status)}
+
+	content, _ := definition["content"].(map[string]interface{})
+	if len(content) == 0 {
+		return builders
+	}
+
+	contentType := openAPIContentType(content)
+	media := o.document.resolve(content[contentType])
+
+	body, ok := o.example(media)
+	if !ok {
+		return append(builders, ResponseSetHeader("content-type", contentType))
+	}
+
+	var data []byte
+
+	if text, isString := body.(string); isString && !isJSONContentType(contentType) {
+		data = []byte(text)
+	} else {
+		data, _ = json.Marshal(body)
+	}
+
+	return append(builders, ResponseSetHeader("content-type", contentType), ResponseBody(data))
+}
+
+// responseDefinition finds the response to use for the operation, preferring the lowest successful status code, then
+// the default response, and then the lowest status code of any kind.
+func (o openAPIOperation) responseDefinition() (int, map[string]interface{}) {
+	codes := []int{}
+	definitions := map[int]interface{}{}
+
+	for key, definition := range o.responses {
+		code, err := strconv.Atoi(strings.ReplaceAll(strings.ToUpper(key), "XX", "00"))
+		if err != nil {
+			continue
+		}
+
+		if _, ok := definitions[code]; !ok || !strings.HasSuffix(strings.ToUpper(key), "XX") {
+			definitions[code] = definition
+		}
+
+		codes = append(codes, code)
+	}
+
+	sort.Ints(codes)
+
+	for _, code := range codes {
+		if code >= 200 && code < 300 {
+			return code, o.document.resolve(definitions[code])
+		}
+	}
+
+	if definition, ok := o.responses["default"]; ok {
+		return http.StatusOK, o.document.resolve(definition)
+	}
+
+	if len(codes) > 0 {
+		return codes[0], o.document.resolve(definitions[codes[0]])
+	}
+
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// example finds the example value for the provided media type, preferring an explicit example, then the first of the
+// named examples, and finally one generated from the schema.
+func (o openAPIOperation) example(media map[string]interface{}) (interface{}, bool) {
+	if example, ok := media["example"]; ok {
+		return example, true
+	}
+
+	if examples, ok := media["examples"].(map[string]interface{}); ok && len(examples) > 0 {
+		names := make([]string, 0, len(examples))
+		for name := range examples {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		if value, ok := o.document.resolve(examples[names[0]])["value"]; ok {
+			return value, true
+		}
+	}
+
+	if schema, ok := media["schema"]; ok {
+		return o.document.generate(schema, 0), true
+	}
+
+	return nil, false
+}
+
+// openAPIContentType chooses the content type to use from the provided content definitions, preferring JSON.
+func openAPIContentType(content map[string]interface{}) string {
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+
+	sort.Strings(types)
+
+	for _, contentType := range types {
+		if isJSONContentType(contentType) {
+			return contentType
+		}
+	}
+
+	return types[0]
+}
+
+// isJSONContentType checks if the provided content type is a JSON one.
+func isJSONContentType(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
+
+// validator builds a `ResponseBuilder` that checks the incoming request conforms to the operation, reporting any
+// violations to the provided reporter.
+func (o openAPIOperation) validator(reporter Reporter) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		violations := o.validate(req)
+
+		if len(violations) > 0 {
+			reporter.Errorf("Request %s %s does not conform to the OpenAPI operation %s %s:\n    %s", req.Method,
+				req.URL.RequestURI(), o.method, o.path, strings.Join(violations, "\n    "))
+		}
+	})
+}
+
+// validate checks the request against the parameters and request body of the operation, returning a description of
+// every violation.
+func (o openAPIOperation) validate(req *http.Request) []string {
+	violations := []string{}
+
+	for _, parameter := range o.parameters {
+		violations = append(violations, o.validateParameter(req, parameter)...)
+	}
+
+	return append(violations, o.validateBody(req)...)
+}
+
+// validateParameter checks the value of a single parameter of the request. The `Accept`, `Content-Type` and
+// `Authorization` header parameters are ignored, as the OpenAPI specification requires.
+func (o openAPIOperation) validateParameter(req *http.Request, parameter map[string]interface{}) []string {
+	name, _ := parameter["name"].(string)
+	in, _ := parameter["in"].(string)
+	required, _ := parameter["required"].(bool)
+
+	if in == "header" && openAPIIgnoredHeaders[http.CanonicalHeaderKey(name)] {
+		return nil
+	}
+	location := fmt.Sprintf("%s parameter %s", in, name)
+
+	var values []string
+
+	switch in {
+	case "path":
+		if value, ok := PathParams(req)[name]; ok {
+			values = []string{value}
+		}
+	case "query":
+		values = req.URL.Query()[name]
+	case "header":
+		values = req.Header.Values(name)
+	case "cookie":
+		if cookie, err := req.Cookie(name); err == nil {
+			values = []string{cookie.Value}
+		}
+	}
+
+	if len(values) == 0 {
+		if required || in == "path" {
+			return []string{fmt.Sprintf("%s: is required", location)}
+		}
+
+		return nil
+	}
+
+	schema := o.document.resolve(parameter["schema"])
+
+	var value interface{}
+
+	if schema["type"] == "array" {
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+
+		items := []interface{}{}
+		for _, item := range values {
+			items = append(items, parseOpenAPIParameter(item, o.document.resolve(schema["items"])))
+		}
+
+		value = items
+	} else {
+		value = parseOpenAPIParameter(values[0], schema)
+	}
+
+	return o.document.validateSchema(value, schema, location)
+}
+
+// parseOpenAPIParameter converts the string value of a parameter into the type required by its schema, leaving it as
+// a string if it can not be converted so that validation will report it.
+func parseOpenAPIParameter(value string, schema map[string]interface{}) interface{} {
+	switch schema["type"] {
+	case "integer", "number":
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case "boolean":
+		if boolean, err := strconv.ParseBool(value); err == nil {
+			return boolean
+		}
+	}
+
+	return value
+}
+
+// validateBody checks the body of the request against the request body of the operation.
+func (o openAPIOperation) validateBody(req *http.Request) []string {
+	if len(o.requestBody) == 0 {
+		return nil
+	}
+
+	body := RequestBody(req)
+	required, _ := o.requestBody["required"].(bool)
+
+	if len(body) == 0 {
+		if required {
+			return []string{"body: is required"}
+		}
+
+		return nil
+	}
+
+	content, _ := o.requestBody["content"].(map[string]interface{})
+	contentType := strings.TrimSpace(strings.Split(req.Header.Get("Content-Type"), ";")[0])
+
+	media, ok := content[contentType]
+	if !ok {
+		return []string{fmt.Sprintf("body: content type %q is not allowed", contentType)}
+	}
+
+	if !isJSONContentType(contentType) {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{fmt.Sprintf("body: is not valid JSON: %v", err)}
+	}
+
+	return o.document.validateSchema(value, o.document.resolve(media)["schema"], "body")
+}
+
+// MountOpenAPI will load the OpenAPI 3 document from the provided JSON or YAML file, and mount a match for every
+// operation in it. Only local references such as `#/components/schemas/Pet` are followed. The path of every operation
+// is treated as a path template relative to the path of the first entry in `servers`, with literal paths mounted before
+// templated ones.
+//
+// Each operation responds with its lowest `2xx` status code, or else `200` for its `default` response, or else its
+// lowest status code of any kind. The body uses the JSON media type if there is one, and comes from the `example` of
+// the media type, then the first of its named `examples` by name, and finally an example generated from its `schema`.
+func (s *server) MountOpenAPI(path string, opts ...OpenAPIOption) ([]*Match, error) {
+	cfg := openAPIConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	document, err := loadOpenAPI(path)
+	if err != nil {
+		return nil, err
+	}
+
+	base := document.basePath()
+	matches := []*Match{}
+
+	for _, operation := range document.operations() {
+		if _, _, err := parsePathTemplate(base + operation.path); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		responses := []ResponseBuilder{}
+		if cfg.validate {
+			responses = append(responses, operation.validator(s.reporter))
+		}
+
+		responses = append(responses, operation.response())
+
+		match := s.Matches(MatchMethod(operation.method), MatchURLPathTemplate(base+operation.path)).
+			RespondsWith(responses...)
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
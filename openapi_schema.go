@@ -0,0 +1,363 @@
+package gomockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// openAPIMaxDepth is the deepest that schemas will be followed when generating examples, to stop recursive schemas from
+// generating forever.
+const openAPIMaxDepth = 10
+
+// openAPIUUID is the pattern that values with the `uuid` format must match.
+var openAPIUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// generate builds an example value that conforms to the provided schema, using any example, default or enum values
+// from the schema where possible.
+func (d *openAPIDocument) generate(value interface{}, depth int) interface{} {
+	schema := d.resolve(value)
+
+	if depth > openAPIMaxDepth {
+		return nil
+	}
+
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+
+	if def, ok := schema["default"]; ok {
+		return def
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		merged := map[string]interface{}{}
+
+		for _, part := range allOf {
+			if object, ok := d.generate(part, depth+1).(map[string]interface{}); ok {
+				for key, value := range object {
+					merged[key] = value
+				}
+			}
+		}
+
+		return merged
+	}
+
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options, ok := schema[key].([]interface{}); ok && len(options) > 0 {
+			return d.generate(options[0], depth+1)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		properties, _ := schema["properties"].(map[string]interface{})
+		object := map[string]interface{}{}
+
+		for name, property := range properties {
+			object[name] = d.generate(property, depth+1)
+		}
+
+		return object
+	case "array":
+		return []interface{}{d.generate(schema["items"], depth+1)}
+	case "string":
+		return generateOpenAPIString(schema)
+	case "integer", "number":
+		if minimum, ok := toFloat(schema["minimum"]); ok {
+			return minimum
+		}
+
+		return 0
+	case "boolean":
+		return false
+	}
+
+	return nil
+}
+
+// generateOpenAPIString builds an example string for the provided schema, based on its format.
+func generateOpenAPIString(schema map[string]interface{}) string {
+	switch schema["format"] {
+	case "date-time":
+		return "1970-01-01T00:00:00Z"
+	case "date":
+		return "1970-01-01"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	}
+
+	return "string"
+}
+
+// schemaType returns the type of the provided schema, inferring `object` if it has properties but no type.
+func schemaType(schema map[string]interface{}) string {
+	if t, ok := schema["type"].(string); ok {
+		return t
+	}
+
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+
+	return ""
+}
+
+// validateSchema checks that the value conforms to the provided schema, returning a description of every violation
+// found, prefixed by the location of the value.
+func (d *openAPIDocument) validateSchema(value, schemaValue interface{}, location string) []string {
+	schema := d.resolve(schemaValue)
+
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable || len(schema) == 0 {
+			return nil
+		}
+	}
+
+	violations := []string{}
+
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, part := range allOf {
+			violations = append(violations, d.validateSchema(value, part, location)...)
+		}
+	}
+
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options, ok := schema[key].([]interface{}); ok && !d.matchesAnySchema(value, options, location) {
+			violations = append(violations, fmt.Sprintf("%s: does not match any of the %s schemas", location, key))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !inEnum(value, enum) {
+		violations = append(violations, fmt.Sprintf("%s: expected one of %s, got %s", location, jsonString(enum),
+			jsonString(value)))
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		violations = append(violations, d.validateObject(value, schema, location)...)
+	case "array":
+		violations = append(violations, d.validateArray(value, schema, location)...)
+	case "string":
+		violations = append(violations, validateString(value, schema, location)...)
+	case "integer", "number":
+		violations = append(violations, validateNumber(value, schema, location)...)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			violations = append(violations, fmt.Sprintf("%s: expected boolean, got %s", location, jsonString(value)))
+		}
+	}
+
+	return violations
+}
+
+// matchesAnySchema checks if the value conforms to at least one of the provided schemas.
+func (d *openAPIDocument) matchesAnySchema(value interface{}, schemas []interface{}, location string) bool {
+	for _, schema := range schemas {
+		if len(d.validateSchema(value, schema, location)) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateObject checks that the value is an object that conforms to the provided object schema.
+func (d *openAPIDocument) validateObject(value interface{}, schema map[string]interface{}, location string) []string {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected object, got %s", location, jsonString(value))}
+	}
+
+	violations := []string{}
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	required, _ := schema["required"].([]interface{})
+	for _, name := range required {
+		if _, ok := object[fmt.Sprint(name)]; !ok {
+			violations = append(violations, fmt.Sprintf("%s.%v: is required", location, name))
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := properties[name]; ok {
+			violations = append(violations, d.validateSchema(object[name], property, location+"."+name)...)
+
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				violations = append(violations, fmt.Sprintf("%s.%s: is not allowed", location, name))
+			}
+		case map[string]interface{}:
+			violations = append(violations, d.validateSchema(object[name], additional, location+"."+name)...)
+		}
+	}
+
+	return violations
+}
+
+// validateArray checks that the value is an array that conforms to the provided array schema.
+func (d *openAPIDocument) validateArray(value interface{}, schema map[string]interface{}, location string) []string {
+	array, ok := value.([]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected array, got %s", location, jsonString(value))}
+	}
+
+	violations := []string{}
+
+	if minItems, ok := toFloat(schema["minItems"]); ok && float64(len(array)) < minItems {
+		violations = append(violations, fmt.Sprintf("%s: expected at least %v items, got %d", location, minItems,
+			len(array)))
+	}
+
+	if maxItems, ok := toFloat(schema["maxItems"]); ok && float64(len(array)) > maxItems {
+		violations = append(violations, fmt.Sprintf("%s: expected at most %v items, got %d", location, maxItems,
+			len(array)))
+	}
+
+	for i, item := range array {
+		violations = append(violations, d.validateSchema(item, schema["items"], fmt.Sprintf("%s[%d]", location, i))...)
+	}
+
+	return violations
+}
+
+// validateString checks that the value is a string that conforms to the provided string schema.
+func validateString(value interface{}, schema map[string]interface{}, location string) []string {
+	s, ok := value.(string)
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected string, got %s", location, jsonString(value))}
+	}
+
+	violations := []string{}
+	length := float64(utf8.RuneCountInString(s))
+
+	if minLength, ok := toFloat(schema["minLength"]); ok && length < minLength {
+		violations = append(violations, fmt.Sprintf("%s: expected at least %v characters, got %q", location,
+			minLength, s))
+	}
+
+	if maxLength, ok := toFloat(schema["maxLength"]); ok && length > maxLength {
+		violations = append(violations, fmt.Sprintf("%s: expected at most %v characters, got %q", location,
+			maxLength, s))
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(s) {
+			violations = append(violations, fmt.Sprintf("%s: expected to match regex %s, got %q", location, pattern, s))
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok && !validFormat(format, s) {
+		violations = append(violations, fmt.Sprintf("%s: expected %s format, got %q", location, format, s))
+	}
+
+	return violations
+}
+
+// validFormat checks if the string is valid for the provided format. Unknown formats are always valid.
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+
+		return err == nil
+	case "uuid":
+		return openAPIUUID.MatchString(s)
+	}
+
+	return true
+}
+
+// validateNumber checks that the value is a number that conforms to the provided numeric schema.
+func validateNumber(value interface{}, schema map[string]interface{}, location string) []string {
+	number, ok := toFloat(value)
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", location, schema["type"], jsonString(value))}
+	}
+
+	violations := []string{}
+
+	if schema["type"] == "integer" && number != math.Trunc(number) {
+		violations = append(violations, fmt.Sprintf("%s: expected integer, got %v", location, number))
+	}
+
+	exclusiveMinimum, _ := schema["exclusiveMinimum"].(bool)
+	if minimum, ok := toFloat(schema["minimum"]); ok && (number < minimum || (exclusiveMinimum && number == minimum)) {
+		violations = append(violations, fmt.Sprintf("%s: expected at least %v, got %v", location, minimum, number))
+	}
+
+	exclusiveMaximum, _ := schema["exclusiveMaximum"].(bool)
+	if maximum, ok := toFloat(schema["maximum"]); ok && (number > maximum || (exclusiveMaximum && number == maximum)) {
+		violations = append(violations, fmt.Sprintf("%s: expected at most %v, got %v", location, maximum, number))
+	}
+
+	return violations
+}
+
+// inEnum checks if the value is one of the values in the enum, comparing their JSON encodings so that numbers decoded
+// from JSON and YAML compare equal.
+func inEnum(value interface{}, enum []interface{}) bool {
+	encoded := jsonString(value)
+
+	for _, option := range enum {
+		if jsonString(option) == encoded {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonString returns the JSON encoding of the value, for use in descriptions and comparisons.
+func jsonString(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
+
+// toFloat converts any numeric value decoded from JSON or YAML into a float.
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,190 @@
+package gomockserver_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/sazzer/gomockserver"
+)
+
+// openAPIRequest will make a request to the provided URL with the given body and headers, returning the response and
+// body.
+func openAPIRequest(t *testing.T, method, url, body string, headers map[string]string) (*http.Response, string) {
+	t.Helper()
+	is := is.New(t)
+
+	req, err := http.NewRequestWithContext(context.Background(), method, url, strings.NewReader(body))
+	is.NoErr(err)
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	output, err := ioutil.ReadAll(resp.Body)
+	is.NoErr(err)
+
+	return resp, string(output)
+}
+
+func TestMountOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		headers     map[string]string
+		status      int
+		contentType string
+		output      string
+	}{
+		{name: "Example", method: http.MethodGet, path: "/v1/pets", status: http.StatusOK,
+			contentType: "application/json", output: `[{"id":1,"name":"Rex"}]`},
+		{name: "Generated from schema", method: http.MethodPost, path: "/v1/pets",
+			body: `{"name":"Rex"}`, headers: map[string]string{"Content-Type": "application/json"},
+			status: http.StatusCreated, contentType: "application/json",
+			output: `{"createdAt":"1970-01-01T00:00:00Z","id":0,"name":"string","tag":"dog"}`},
+		{name: "Literal path before template", method: http.MethodGet, path: "/v1/pets/mine", status: http.StatusOK,
+			contentType: "text/plain", output: "No pets"},
+		{name: "Named examples", method: http.MethodGet, path: "/v1/pets/1",
+			headers: map[string]string{"X-Request-Id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+			status:  http.StatusOK, contentType: "application/json", output: `{"id":1,"name":"Rex","tag":"dog"}`},
+		{name: "No content", method: http.MethodDelete, path: "/v1/pets/1", status: http.StatusNoContent},
+		{name: "Outside base path", method: http.MethodGet, path: "/pets", status: http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8", output: "404 page not found\n"},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
+			defer server.Close()
+
+			matches, err := server.MountOpenAPI("testdata/openapi/petstore.yaml")
+			is.NoErr(err)
+			is.Equal(len(matches), 5)
+
+			resp, body := openAPIRequest(t, tt.method, server.URL()+tt.path, tt.body, tt.headers)
+
+			is.Equal(resp.StatusCode, tt.status)
+			is.Equal(resp.Header.Get("Content-Type"), tt.contentType)
+			is.Equal(body, tt.output)
+		})
+	}
+}
+
+func TestMountOpenAPIValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		headers    map[string]string
+		violations []string
+	}{
+		{name: "Valid query", method: http.MethodGet, path: "/v1/pets?limit=10"},
+		{name: "Query too large", method: http.MethodGet, path: "/v1/pets?limit=1000",
+			violations: []string{"query parameter limit: expected at most 100, got 1000"}},
+		{name: "Query wrong type", method: http.MethodGet, path: "/v1/pets?limit=ten",
+			violations: []string{`query parameter limit: expected integer, got "ten"`}},
+		{name: "Valid body", method: http.MethodPost, path: "/v1/pets", body: `{"name":"Rex","tag":"dog"}`,
+			headers: map[string]string{"Content-Type": "application/json"}},
+		{name: "Missing body", method: http.MethodPost, path: "/v1/pets",
+			headers: map[string]string{"Content-Type": "application/json"}, violations: []string{"body: is required"}},
+		{name: "Invalid body", method: http.MethodPost, path: "/v1/pets", body: `{"name":"","tag":"fish","age":3}`,
+			headers: map[string]string{"Content-Type": "application/json"}, violations: []string{
+				"body.age: is not allowed",
+				`body.name: expected at least 1 characters, got ""`,
+				`body.tag: expected one of ["dog","cat"], got "fish"`,
+			}},
+		{name: "Wrong content type", method: http.MethodPost, path: "/v1/pets", body: `name=Rex`,
+			headers:    map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			violations: []string{`body: content type "application/x-www-form-urlencoded" is not allowed`}},
+		{name: "Invalid path and missing header", method: http.MethodGet, path: "/v1/pets/abc", violations: []string{
+			`path parameter petId: expected integer, got "abc"`,
+			"header parameter X-Request-Id: is required",
+		}},
+		{name: "Ignored headers", method: http.MethodGet, path: "/v1/pets/mine",
+			headers: map[string]string{"Accept": "application/json"}},
+		{name: "Invalid header format", method: http.MethodGet, path: "/v1/pets/1",
+			headers:    map[string]string{"X-Request-Id": "abc"},
+			violations: []string{`header parameter X-Request-Id: expected uuid format, got "abc"`}},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			reporter := &recordingReporter{}
+
+			server, err := gomockserver.NewServer(reporter)
+			is.NoErr(err)
+
+			_, err = server.MountOpenAPI("testdata/openapi/petstore.yaml", gomockserver.OpenAPIValidateRequests())
+			is.NoErr(err)
+
+			_, _ = openAPIRequest(t, tt.method, server.URL()+tt.path, tt.body, tt.headers)
+
+			server.Verify()
+
+			if len(tt.violations) == 0 {
+				is.Equal(len(reporter.errors), 0)
+
+				return
+			}
+
+			is.Equal(len(reporter.errors), 1)
+
+			lines := strings.Split(reporter.errors[0], "\n    ")
+			is.True(strings.HasPrefix(lines[0], "Request "+tt.method+" "+tt.path+" does not conform to the OpenAPI"))
+			is.Equal(lines[1:], tt.violations)
+		})
+	}
+}
+
+func TestMountOpenAPIUnquotedStatusCodes(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	_, err := server.MountOpenAPI("testdata/openapi/unquoted.yaml")
+	is.NoErr(err)
+
+	resp, body := openAPIRequest(t, http.MethodGet, server.URL()+"/orders/1", "", nil)
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(resp.Header.Get("Content-Type"), "application/json")
+	is.Equal(body, `{"id":1,"lines":{"1":"Widget","2":"Gadget"}}`)
+}
+
+func TestMountOpenAPIUnsupportedVersion(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	_, err := server.MountOpenAPI("testdata/openapi/swagger.yaml")
+	is.True(errors.Is(err, gomockserver.ErrUnsupportedOpenAPIVersion))
+}
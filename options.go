@@ -0,0 +1,56 @@
+package gomockserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+)
+
+// DefaultMaxBodySize is the largest request body, in bytes, that the mock server will accept unless configured
+// otherwise.
+const DefaultMaxBodySize = 10 * 1024 * 1024
+
+// Option represents a configuration option to apply when creating a new mock server.
+type Option func(*config)
+
+// config represents the configuration of a mock server that is built up from the provided options.
+type config struct {
+	maxBodySize    int64
+	allowUnmatched bool
+	tls            bool
+	certificate    *tls.Certificate
+	clientCAs      *x509.CertPool
+	http2          bool
+	address        string
+	listener       net.Listener
+	unixSocket     string
+	inProcess      bool
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		maxBodySize: DefaultMaxBodySize,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WithMaxBodySize sets the largest request body, in bytes, that the mock server will accept.
+// Any request with a larger body will fail the test and receive an `HTTP 413 Request Entity Too Large` response.
+func WithMaxBodySize(size int64) Option {
+	return func(c *config) {
+		c.maxBodySize = size
+	}
+}
+
+// WithAllowUnmatchedRequests stops the test from failing when the mock server receives requests that are not matched
+// by any `Match`. Such requests will still receive an `HTTP 404 Not Found` response.
+func WithAllowUnmatchedRequests() Option {
+	return func(c *config) {
+		c.allowUnmatched = true
+	}
+}
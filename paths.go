@@ -0,0 +1,181 @@
+package gomockserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// PathWildcard is the name of the path parameter that captures the remainder of the path when a path template ends in
+// a trailing `*`.
+const PathWildcard = "*"
+
+// pathSegment represents a single segment of a path template.
+type pathSegment struct {
+	literal string
+	name    string
+	pattern *regexp.Regexp
+}
+
+// matches checks if the provided escaped segment of a request path matches this segment of the template.
+func (s pathSegment) matches(segment string) bool {
+	if s.name == "" {
+		return s.literal == segment
+	}
+
+	if segment == "" {
+		return false
+	}
+
+	if s.pattern == nil {
+		return true
+	}
+
+	value, err := url.PathUnescape(segment)
+	if err != nil {
+		return false
+	}
+
+	return s.pattern.MatchString(value)
+}
+
+// parsePathTemplate will parse the provided path template into the segments that make it up, and whether or not it
+// ends with a wildcard.
+func parsePathTemplate(template string) ([]pathSegment, bool, error) {
+	parts := strings.Split(template, "/")
+	segments := make([]pathSegment, 0, len(parts))
+	wildcard := false
+
+	for i, part := range parts {
+		switch {
+		case part == PathWildcard:
+			if i != len(parts)-1 {
+				return nil, false, fmt.Errorf("wildcard must be the final segment of path template %q", template)
+			}
+
+			wildcard = true
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+			segment, err := parsePathParameter(part[1 : len(part)-1])
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid path template %q: %w", template, err)
+			}
+
+			segments = append(segments, segment)
+		default:
+			segments = append(segments, pathSegment{literal: part})
+		}
+	}
+
+	return segments, wildcard, nil
+}
+
+// parsePathParameter will parse the contents of a single `{name}` or `{name:pattern}` path template segment.
+func parsePathParameter(definition string) (pathSegment, error) {
+	name := definition
+	pattern := ""
+
+	if idx := strings.Index(definition, ":"); idx >= 0 {
+		name = definition[:idx]
+		pattern = definition[idx+1:]
+	}
+
+	if name == "" {
+		return pathSegment{}, fmt.Errorf("path parameter %q has no name", definition)
+	}
+
+	segment := pathSegment{name: name}
+
+	if pattern != "" {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return pathSegment{}, fmt.Errorf("path parameter %q has an invalid pattern: %w", name, err)
+		}
+
+		segment.pattern = re
+	}
+
+	return segment, nil
+}
+
+// MatchURLPathTemplate builds a `MatchRule` to check if the URL Path of the request matches the provided template.
+// Segments of the template can be literal strings, which must match exactly, or path parameters in the form `{name}`,
+// which will match any single non-empty segment. A path parameter can be constrained by a regular expression using the
+// form `{name:pattern}`, in which case the entire segment must match the pattern. The template can also end with `/*`,
+// which will match any remaining path, including none at all.
+//
+// The values captured by the path parameters are available to any `ResponseBuilder` through `PathParam` and
+// `PathParams`, with the remaining path matched by a trailing wildcard available as `PathWildcard`.
+//
+// This will panic if the template is not valid.
+func MatchURLPathTemplate(template string) MatchRule {
+	segments, wildcard, err := parsePathTemplate(template)
+	if err != nil {
+		panic(err)
+	}
+
+	return MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		uri, err := url.ParseRequestURI(r.RequestURI)
+		if err != nil {
+			return false, fmt.Sprintf("URL: unable to parse %s: %v", r.RequestURI, err)
+		}
+
+		reason := fmt.Sprintf("URL path: expected to match template %s, got %s", template, uri.EscapedPath())
+
+		parts := strings.Split(uri.EscapedPath(), "/")
+		if len(parts) < len(segments) || (!wildcard && len(parts) != len(segments)) {
+			return false, reason
+		}
+
+		params := map[string]string{}
+
+		for i, segment := range segments {
+			if !segment.matches(parts[i]) {
+				return false, reason
+			}
+
+			if segment.name != "" {
+				value, _ := url.PathUnescape(parts[i])
+				params[segment.name] = value
+			}
+		}
+
+		if wildcard {
+			value, _ := url.PathUnescape(strings.Join(parts[len(segments):], "/"))
+			params[PathWildcard] = value
+		}
+
+		if state := getRequestState(r); state != nil {
+			for name, value := range params {
+				state.pathParams[name] = value
+			}
+		}
+
+		return true, reason
+	})
+}
+
+// PathParams returns every path parameter captured from the URL of the incoming request by a `MatchURLPathTemplate`
+// rule.
+func PathParams(r *http.Request) map[string]string {
+	params := map[string]string{}
+
+	if state := getRequestState(r); state != nil {
+		for name, value := range state.pathParams {
+			params[name] = value
+		}
+	}
+
+	return params
+}
+
+// PathParam returns the value of a single path parameter captured from the URL of the incoming request by a
+// `MatchURLPathTemplate` rule, or the empty string if there is no such parameter.
+func PathParam(r *http.Request, name string) string {
+	if state := getRequestState(r); state != nil {
+		return state.pathParams[name]
+	}
+
+	return ""
+}
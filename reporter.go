@@ -0,0 +1,37 @@
+package gomockserver
+
+import (
+	"log"
+)
+
+// Reporter is the means by which the mock server logs details of the requests it receives and reports failures, such
+// as unmatched requests or unmet expectations. This is satisfied by `testing.TB`.
+type Reporter interface {
+	// Logf logs a message that is useful for diagnosing failures.
+	Logf(format string, args ...interface{})
+	// Errorf reports a failure.
+	Errorf(format string, args ...interface{})
+}
+
+// logReporter is a `Reporter` that writes everything to a logger.
+type logReporter struct {
+	logger *log.Logger
+}
+
+// LogReporter builds a `Reporter` that writes every message and failure to the provided logger, or to the standard
+// logger if this is `nil`. This is useful when running the mock server outside of tests.
+func LogReporter(logger *log.Logger) Reporter {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return logReporter{logger: logger}
+}
+
+func (r logReporter) Logf(format string, args ...interface{}) {
+	r.logger.Printf(format, args...)
+}
+
+func (r logReporter) Errorf(format string, args ...interface{}) {
+	r.logger.Printf("ERROR: "+format, args...)
+}
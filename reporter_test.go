@@ -0,0 +1,132 @@
+package gomockserver_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/sazzer/gomockserver"
+)
+
+// recordingReporter is a `gomockserver.Reporter` that records everything reported to it.
+type recordingReporter struct {
+	mutex  sync.Mutex
+	logs   []string
+	errors []string
+}
+
+func (r *recordingReporter) Logf(format string, args ...interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.logs = append(r.logs, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingReporter) Errorf(format string, args ...interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestNewServerReporter(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	reporter := &recordingReporter{}
+
+	server, err := gomockserver.NewServer(reporter)
+	is.NoErr(err)
+
+	server.Matches(gomockserver.MatchURLPath("/expected")).Times(1)
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/unexpected")
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusNotFound)
+
+	server.Verify()
+
+	is.Equal(len(reporter.logs), 1)
+	is.True(strings.HasPrefix(reporter.logs[0], "Unmatched request: GET /unexpected"))
+
+	is.Equal(len(reporter.errors), 1)
+	is.True(strings.Contains(reporter.errors[0], "Match 1: expected exactly 1 requests, got 0"))
+	is.True(strings.Contains(reporter.errors[0], "Unmatched request: GET"))
+}
+
+func TestNewServerError(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	_, err := gomockserver.NewServer(&recordingReporter{}, gomockserver.WithAddress("not an address"))
+	is.True(err != nil)
+
+	_, err = gomockserver.NewServer(&recordingReporter{}, gomockserver.WithInProcessTransport(), gomockserver.WithTLS())
+	is.True(err != nil)
+}
+
+func TestLogReporter(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	var output bytes.Buffer
+
+	server, err := gomockserver.NewServer(gomockserver.LogReporter(log.New(&output, "", 0)))
+	is.NoErr(err)
+
+	server.Matches(gomockserver.MatchURLPath("/expected")).Times(1)
+
+	server.Verify()
+
+	is.Equal(output.String(), "ERROR: Mock server expectations were not met:\n"+
+		"    Match 1: expected exactly 1 requests, got 0\n")
+}
+
+func BenchmarkServer(b *testing.B) {
+	is := is.New(b)
+
+	server := gomockserver.New(b, gomockserver.WithInProcessTransport())
+
+	server.Matches(gomockserver.MatchURLPathTemplate("/items/{id}")).
+		RespondsWith(gomockserver.ResponseJSON(map[string]string{"hello": "world"}))
+
+	client := server.Client()
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		resp, err := client.Get(fmt.Sprintf("%s/items/%d", server.URL(), i)) //nolint:noctx
+		is.NoErr(err)
+
+		_, _ = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+}
+
+func ExampleNewServer() {
+	server, err := gomockserver.NewServer(gomockserver.LogReporter(nil), gomockserver.WithInProcessTransport())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer server.Verify()
+
+	server.Matches(gomockserver.MatchRequest(http.MethodGet, "/greeting")).
+		RespondsWith(gomockserver.ResponseJSON("Hello"))
+
+	resp, err := server.Client().Get(server.URL() + "/greeting") //nolint:noctx
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	fmt.Println(resp.StatusCode, string(body))
+	// Output: 200 "Hello"
+}
@@ -0,0 +1,106 @@
+package gomockserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// ErrBodyTooLarge is returned when an incoming request has a body larger than the configured maximum size.
+var ErrBodyTooLarge = errors.New("request body too large")
+
+type requestStateKey struct{}
+
+// requestState represents the details captured about an incoming request while the mock server is handling it.
+type requestState struct {
+	body       []byte
+	pathParams map[string]string
+}
+
+// captureRequest will read the entire body of the incoming request, up to the given maximum size, and return a new
+// request that has these details attached to it.
+func captureRequest(r *http.Request, maxBodySize int64) (*http.Request, error) {
+	state := &requestState{
+		pathParams: map[string]string{},
+	}
+
+	if r.Body != nil {
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+		if err != nil {
+			return nil, err
+		}
+
+		if int64(len(body)) > maxBodySize {
+			return nil, ErrBodyTooLarge
+		}
+
+		state.body = body
+	}
+
+	r = r.WithContext(context.WithValue(r.Context(), requestStateKey{}, state))
+	rewindBody(r)
+
+	return r, nil
+}
+
+func getRequestState(r *http.Request) *requestState {
+	state, _ := r.Context().Value(requestStateKey{}).(*requestState)
+
+	return state
+}
+
+// rewindBody will reset the body of the request so that it can be read again from the start.
+func rewindBody(r *http.Request) {
+	if state := getRequestState(r); state != nil {
+		r.Body = ioutil.NopCloser(bytes.NewReader(state.body))
+	}
+}
+
+// resetRequest will discard anything that a previous `Match` has recorded about the request, and rewind the body, so
+// that the request can be checked against another `Match`.
+func resetRequest(r *http.Request) {
+	if state := getRequestState(r); state != nil {
+		state.pathParams = map[string]string{}
+	}
+
+	rewindBody(r)
+}
+
+// savePathParams will take a copy of the path parameters captured so far for the request, returning a function that
+// will put them back again. This allows a rule that did not pass to discard anything that it captured.
+func savePathParams(r *http.Request) func() {
+	state := getRequestState(r)
+	if state == nil {
+		return func() {}
+	}
+
+	saved := make(map[string]string, len(state.pathParams))
+	for name, value := range state.pathParams {
+		saved[name] = value
+	}
+
+	return func() {
+		state.pathParams = saved
+	}
+}
+
+// RequestBody returns the body of the incoming request.
+// The body is captured once when the request is received, so this can be called from any number of `MatchRule` and
+// `ResponseBuilder` instances and will always return the complete body.
+func RequestBody(r *http.Request) []byte {
+	if state := getRequestState(r); state != nil {
+		return state.body
+	}
+
+	if r.Body == nil {
+		return nil
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body
+}
@@ -1,7 +1,10 @@
 package gomockserver
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"time"
 )
 
 // Response is a representation of the response to send to the client.
@@ -9,18 +12,78 @@ type Response struct {
 	Status  int
 	Headers http.Header
 	Body    []byte
+	// Trailers are headers to send after the body has been written.
+	Trailers http.Header
+	// Delay is how long to wait before sending anything to the client.
+	Delay time.Duration
+	// Stream is a source of chunks to stream to the client as the body. If this is set then `Body` is ignored.
+	Stream BodyStream
+	// ChunkSize is the size of the chunks to send the body in, or zero to send the body all at once. This is ignored if
+	// `Stream` is set.
+	ChunkSize int
+	// ChunkDelay is the source of delays to wait between sending each chunk of the body.
+	ChunkDelay DelayDistribution
+	// Fault is a way in which to misbehave instead of sending a valid response.
+	Fault Fault
+	// upgrade takes over the connection to speak a different protocol instead of sending a normal response.
+	upgrade func(ctx context.Context, w http.ResponseWriter)
 }
 
 // Write will write the response details to the provided response writer.
 func (r Response) Write(w http.ResponseWriter) {
+	r.write(context.Background(), w)
+}
+
+// write will write the response details to the provided response writer, stopping early if the context is cancelled.
+func (r Response) write(ctx context.Context, w http.ResponseWriter) {
+	if closer, ok := r.Stream.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if !sleepContext(ctx, r.Delay) {
+		return
+	}
+
+	if r.upgrade != nil {
+		r.upgrade(ctx, w)
+
+		return
+	}
+
+	if r.Fault != FaultNone {
+		r.writeFault(ctx, w)
+
+		return
+	}
+
 	for name, values := range r.Headers {
 		for _, value := range values {
 			w.Header().Add(name, value)
 		}
 	}
 
+	for name := range r.Trailers {
+		w.Header().Add("Trailer", name)
+	}
+
 	w.WriteHeader(r.Status)
-	_, _ = w.Write(r.Body)
+
+	switch {
+	case r.Stream != nil:
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		r.writeStream(ctx, w, r.Stream)
+	case r.ChunkSize > 0:
+		r.writeStream(ctx, w, &chunksStream{chunks: splitChunks(r.Body, r.ChunkSize)})
+	default:
+		_, _ = w.Write(r.Body)
+	}
+
+	for name, values := range r.Trailers {
+		w.Header()[http.CanonicalHeaderKey(name)] = values
+	}
 }
 
 // ResponseBuilder is a means to contribute to the response to send to the client.
@@ -66,6 +129,17 @@ func ResponseAppendHeader(name, value string) ResponseBuilder {
 	})
 }
 
+// ResponseTrailer will append a new value for the trailer name provided, to be sent after the response body.
+func ResponseTrailer(name, value string) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		if r.Trailers == nil {
+			r.Trailers = http.Header{}
+		}
+
+		r.Trailers.Add(name, value)
+	})
+}
+
 // ResponseBody will indicate the data to return as the response body.
 func ResponseBody(data []byte) ResponseBuilder {
 	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
@@ -0,0 +1,120 @@
+package gomockserver
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ScenarioStarted is the state that every scenario starts in, and returns to when it is reset.
+const ScenarioStarted = "Started"
+
+// scenarios keeps track of the current state of every scenario on a mock server.
+type scenarios struct {
+	mutex  sync.Mutex
+	states map[string]string
+}
+
+func newScenarios() *scenarios {
+	return &scenarios{
+		states: map[string]string{},
+	}
+}
+
+// state returns the current state of the named scenario. This must be called with the mutex held.
+func (s *scenarios) state(name string) string {
+	if state, ok := s.states[name]; ok {
+		return state
+	}
+
+	return ScenarioStarted
+}
+
+// get returns the current state of the named scenario.
+func (s *scenarios) get(name string) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.state(name)
+}
+
+// set changes the current state of the named scenario.
+func (s *scenarios) set(name, state string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.states[name] = state
+}
+
+// reset returns the named scenarios to their starting state, or every scenario if none are named.
+func (s *scenarios) reset(names ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(names) == 0 {
+		s.states = map[string]string{}
+	}
+
+	for _, name := range names {
+		delete(s.states, name)
+	}
+}
+
+// InScenario makes this match part of the named scenario. Scenarios allow for stateful mocks, where a match can
+// require the scenario to be in a given state - using `WhenScenarioStateIs` - and can move the scenario to a new state
+// when it is used - using `WillSetScenarioStateTo`. Every scenario starts in the `ScenarioStarted` state.
+func (m *Match) InScenario(name string) *Match {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.scenario = name
+
+	return m
+}
+
+// WhenScenarioStateIs will make this match only match requests when its scenario is in the given state.
+// This requires the match to be part of a scenario by using `InScenario`.
+func (m *Match) WhenScenarioStateIs(state string) *Match {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.requiredState = state
+
+	return m
+}
+
+// WillSetScenarioStateTo will make this match move its scenario to the given state whenever it is used to respond to
+// a request. This requires the match to be part of a scenario by using `InScenario`.
+func (m *Match) WillSetScenarioStateTo(state string) *Match {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.nextState = state
+
+	return m
+}
+
+// inRequiredState checks if the scenario this match belongs to is in the state required for the match to be used.
+// This must be called with both the match and scenarios mutexes held.
+func (m *Match) inRequiredState() bool {
+	return m.scenario == "" || m.requiredState == "" || m.scenarios.state(m.scenario) == m.requiredState
+}
+
+// explainScenario describes whether the scenario this match belongs to is in the required state, for reporting why a
+// request was not matched.
+func (m *Match) explainScenario() (ruleResult, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.scenario == "" || m.requiredState == "" {
+		return ruleResult{}, false
+	}
+
+	m.scenarios.mutex.Lock()
+	defer m.scenarios.mutex.Unlock()
+
+	return ruleResult{
+		matched: m.inRequiredState(),
+		reason: fmt.Sprintf("scenario %s: expected state %s, got %s", m.scenario, m.requiredState,
+			m.scenarios.state(m.scenario)),
+	}, true
+}
@@ -0,0 +1,86 @@
+package gomockserver
+
+import "fmt"
+
+// SequenceMode determines what a `Match` does once every response in its sequence of responses has been used.
+type SequenceMode int
+
+const (
+	// SequenceRepeatLast will keep responding with the final response in the sequence. This is the default.
+	SequenceRepeatLast SequenceMode = iota
+	// SequenceCycle will start again from the first response in the sequence.
+	SequenceCycle
+	// SequenceFallThrough will stop the `Match` from matching any more requests, so that they fall through to the
+	// next `Match` instead.
+	SequenceFallThrough
+)
+
+// RespondsWithSequence registers an ordered sequence of responses to use for successive requests.
+// The first request handled by this match uses the first response, the second request uses the second, and so on.
+// What happens once every response has been used is determined by `WhenSequenceExhausted`.
+//
+// Any response builders registered with `RespondsWith` are still used for every request, before the one from the
+// sequence. Where a single response in the sequence needs several builders they can be combined with
+// `ResponseBuilders`.
+func (m *Match) RespondsWithSequence(responses ...ResponseBuilder) *Match {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.sequence = append(m.sequence, responses...)
+
+	return m
+}
+
+// WhenSequenceExhausted determines what happens once every response registered with `RespondsWithSequence` has been
+// used.
+func (m *Match) WhenSequenceExhausted(mode SequenceMode) *Match {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.sequenceMode = mode
+
+	return m
+}
+
+// exhausted checks if this match has used every response in its sequence and should no longer match any requests.
+// This must be called with the mutex held.
+func (m *Match) exhausted() bool {
+	return m.sequenceMode == SequenceFallThrough && len(m.sequence) > 0 && m.count >= len(m.sequence)
+}
+
+// nextInSequence returns the response from the sequence to use for the next request, or `nil` if there is no sequence.
+// This must be called with the mutex held.
+func (m *Match) nextInSequence() ResponseBuilder {
+	if len(m.sequence) == 0 {
+		return nil
+	}
+
+	index := m.count
+
+	if index >= len(m.sequence) {
+		switch m.sequenceMode {
+		case SequenceCycle:
+			index %= len(m.sequence)
+		case SequenceRepeatLast, SequenceFallThrough:
+			index = len(m.sequence) - 1
+		}
+	}
+
+	return m.sequence[index]
+}
+
+// explainSequence describes whether this match has used every response in its sequence, for reporting why a request
+// was not matched.
+func (m *Match) explainSequence() (ruleResult, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.exhausted() {
+		return ruleResult{}, false
+	}
+
+	return ruleResult{
+		matched: false,
+		reason:  fmt.Sprintf("response sequence: all %d responses have been used", len(m.sequence)),
+	}, true
+}
@@ -1,42 +1,176 @@
 package gomockserver
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 )
 
+// errTLSNotSupported is returned when TLS is requested for a server that does not listen on a TCP socket.
+var errTLSNotSupported = errors.New("unix sockets and in-process transports can not be used with TLS")
+
 type server struct {
-	t       *testing.T
-	handler *handler
-	server  *httptest.Server
+	reporter       Reporter
+	mutex          sync.Mutex
+	handler        *handler
+	server         *httptest.Server
+	client         *http.Client
+	allowUnmatched bool
 }
 
-// New will create a new mock server ready for use in tests.
-func New(t *testing.T) MockServer {
+// New will create a new mock server ready for use in tests or benchmarks, configured by any options provided.
+// The server will automatically be closed when the test finishes, at which point the test will fail if any `Match`
+// expectations have not been met or if any requests were not matched.
+func New(t testing.TB, opts ...Option) MockServer {
 	t.Helper()
 
+	s, err := newServer(t, opts)
+	if err != nil {
+		t.Fatalf("Failed to start mock server: %v", err)
+	}
+
+	t.Cleanup(s.Verify)
+
+	return s
+}
+
+// NewServer will create a new mock server for use outside of tests, such as in example programs or local development
+// tooling, configured by any options provided. Details of unmatched requests and any failures are sent to the provided
+// reporter. Unlike with `New`, the server is not closed automatically, so `MockServer.Close` or `MockServer.Verify`
+// must be called when it is finished with.
+func NewServer(reporter Reporter, opts ...Option) (MockServer, error) {
+	return newServer(reporter, opts)
+}
+
+// newServer will create and start a new mock server that sends any failures to the provided reporter.
+func newServer(reporter Reporter, opts []Option) (*server, error) {
+	cfg := newConfig(opts)
+
 	handler := handler{
-		t:              t,
-		unmatchedCount: 0,
+		reporter:    reporter,
+		scenarios:   newScenarios(),
+		maxBodySize: cfg.maxBodySize,
+		closed:      make(chan struct{}),
+	}
+
+	s := &server{
+		reporter:       reporter,
+		handler:        &handler,
+		server:         httptest.NewUnstartedServer(&handler),
+		allowUnmatched: cfg.allowUnmatched,
+	}
+
+	listener, err := cfg.listen()
+	if err != nil {
+		s.server.Listener.Close()
+
+		return nil, err
 	}
 
-	return &server{
-		t:       t,
-		handler: &handler,
-		server:  httptest.NewServer(&handler),
+	if listener != nil {
+		s.server.Listener.Close()
+		s.server.Listener = listener
+	}
+
+	pipe, inProcess := s.server.Listener.(*pipeListener)
+
+	unixSocket := ""
+	if s.server.Listener.Addr().Network() == "unix" {
+		unixSocket = s.server.Listener.Addr().String()
+	}
+
+	if cfg.tls && (unixSocket != "" || inProcess) {
+		s.server.Listener.Close()
+
+		return nil, errTLSNotSupported
+	}
+
+	switch {
+	case cfg.tls:
+		s.server.EnableHTTP2 = cfg.http2
+		s.server.TLS = cfg.tlsConfig()
+		s.server.StartTLS()
+	case cfg.http2:
+		s.server.Config.Handler = h2cHandler(&handler)
+		s.client = &http.Client{Transport: newH2CTransport((&net.Dialer{}).DialContext)}
+		s.server.Start()
+	default:
+		s.server.Start()
+	}
+
+	switch {
+	case inProcess:
+		s.server.URL = "http://localhost"
+		s.client = newInProcessClient(pipe, cfg.http2)
+	case unixSocket != "":
+		s.server.URL = "http://localhost"
+		s.client = newUnixSocketClient(unixSocket, cfg.http2)
+	}
+
+	return s, nil
+}
+
+func (s *server) Verify() {
+	if tb, ok := s.reporter.(interface{ Helper() }); ok {
+		tb.Helper()
+	}
+
+	s.Close()
+
+	failures := []string{}
+
+	for i, match := range s.handler.getMatches() {
+		if unmet, ok := match.unmetExpectation(); ok {
+			failures = append(failures, fmt.Sprintf("Match %d: %s", i+1, unmet))
+		}
+	}
+
+	if !s.allowUnmatched {
+		for _, request := range s.handler.getJournal().Unmatched() {
+			failures = append(failures, fmt.Sprintf("Unmatched request: %s", request))
+		}
+	}
+
+	if len(failures) > 0 {
+		s.reporter.Errorf("Mock server expectations were not met:\n    %s", strings.Join(failures, "\n    "))
 	}
 }
 
 func (s *server) Close() {
-	if s.server != nil {
-		s.server.Close()
-		s.server = nil
+	s.mutex.Lock()
+	httpServer, client := s.server, s.client
+	s.mutex.Unlock()
+
+	// The lock is not held while waiting for requests in progress to finish, since they may need it themselves - for
+	// example to build a response using `URL`.
+	s.handler.close()
+
+	if client != nil {
+		client.CloseIdleConnections()
+	}
+
+	if httpServer != nil {
+		httpServer.Close()
 	}
+
+	s.handler.wait()
+
+	s.mutex.Lock()
+	s.server = nil
+	s.mutex.Unlock()
 }
 
 func (s *server) URL() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	if s.server == nil {
-		s.t.Error("Server has been closed")
+		s.reporter.Errorf("Server has been closed")
 	}
 
 	return s.server.URL
@@ -44,10 +178,11 @@ func (s *server) URL() string {
 
 func (s *server) Matches(rules ...MatchRule) *Match {
 	match := &Match{
-		rules: rules,
+		rules:     rules,
+		scenarios: s.handler.scenarios,
 	}
 
-	s.handler.matches = append(s.handler.matches, match)
+	s.handler.addMatch(match)
 
 	return match
 }
@@ -56,13 +191,34 @@ func (s *server) Mount(mock Mock) *Match {
 	match := &Match{
 		rules:     mock.Matches,
 		responses: mock.Response,
+		scenarios: s.handler.scenarios,
 	}
 
-	s.handler.matches = append(s.handler.matches, match)
+	s.handler.addMatch(match)
 
 	return match
 }
 
 func (s *server) UnmatchedCount() int {
-	return s.handler.unmatchedCount
+	return len(s.handler.getJournal().Unmatched())
+}
+
+func (s *server) Requests() RecordedRequests {
+	return s.handler.getJournal()
+}
+
+func (s *server) UnmatchedRequests() RecordedRequests {
+	return s.handler.getJournal().Unmatched()
+}
+
+func (s *server) ScenarioState(name string) string {
+	return s.handler.scenarios.get(name)
+}
+
+func (s *server) SetScenarioState(name, state string) {
+	s.handler.scenarios.set(name, state)
+}
+
+func (s *server) ResetScenarios(names ...string) {
+	s.handler.scenarios.reset(names...)
 }
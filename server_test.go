@@ -1,14 +1,24 @@
 package gomockserver_test
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/matryer/is"
 	"github.com/sazzer/gomockserver"
 )
@@ -66,7 +76,7 @@ func TestNoRoutes(t *testing.T) {
 			t.Parallel()
 			is := is.New(t)
 
-			server := gomockserver.New(t)
+			server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
 			defer server.Close()
 
 			resp := makeRequest(t, tt, server.URL())
@@ -101,7 +111,7 @@ func TestMatchRequestDoesntMatchURL(t *testing.T) {
 	t.Parallel()
 	is := is.New(t)
 
-	server := gomockserver.New(t)
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
 	defer server.Close()
 
 	server.Matches(gomockserver.MatchRequest("GET", "/testing/abc"))
@@ -116,7 +126,7 @@ func TestMatchRequestDoesntMatchMethod(t *testing.T) {
 	t.Parallel()
 	is := is.New(t)
 
-	server := gomockserver.New(t)
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
 	defer server.Close()
 
 	server.Matches(gomockserver.MatchRequest("POST", "/testing/abc"))
@@ -209,7 +219,7 @@ func TestMatchJSONBodyFullMissingKey(t *testing.T) {
 	t.Parallel()
 	is := is.New(t)
 
-	server := gomockserver.New(t)
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
 	defer server.Close()
 
 	server.Matches(gomockserver.MatchJSONFull(map[string]interface{}{
@@ -315,7 +325,7 @@ func TestMatchJSONBodyCompatibleExtraKey(t *testing.T) {
 	t.Parallel()
 	is := is.New(t)
 
-	server := gomockserver.New(t)
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
 	defer server.Close()
 
 	server.Matches(gomockserver.MatchJSONCompatible(map[string]interface{}{
@@ -439,7 +449,7 @@ func TestMatchQueryWrongValue(t *testing.T) {
 	t.Parallel()
 	is := is.New(t)
 
-	server := gomockserver.New(t)
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
 	defer server.Close()
 
 	match := server.Matches(gomockserver.MatchRequest("GET", "/testing"), gomockserver.MatchURLQuery("answer", "42"))
@@ -504,3 +514,1164 @@ func TestSimpleMock(t *testing.T) {
 	is.Equal(resp.Header.Get("content-type"), "application/json")
 	is.Equal(resp.Header.Values("X-Test"), []string{"1", "2"})
 }
+
+func TestMultipleJSONMatchesSameRequest(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	first := server.Matches(gomockserver.MatchJSONCompatible(map[string]interface{}{"a": 2}))
+	second := server.Matches(gomockserver.MatchJSONCompatible(map[string]interface{}{"a": 1}))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL(),
+		bytes.NewReader([]byte(`{"a": 1, "b": 2}`)))
+	is.NoErr(err)
+
+	resp, err := http.DefaultClient.Do(req)
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(first.Count(), 0)
+	is.Equal(second.Count(), 1)
+}
+
+func TestMultipleBodyRulesSameMatch(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchJSONCompatible(map[string]interface{}{"a": 1}),
+		gomockserver.MatchRuleFunc(func(r *http.Request) bool {
+			body, err := ioutil.ReadAll(r.Body)
+
+			return err == nil && bytes.Contains(body, []byte(`"b"`))
+		})).
+		RespondsWith(gomockserver.ResponseBuilderFunc(func(r *gomockserver.Response, req *http.Request) {
+			r.Body = gomockserver.RequestBody(req)
+		}))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL(),
+		bytes.NewReader([]byte(`{"a": 1, "b": 2}`)))
+	is.NoErr(err)
+
+	resp, err := http.DefaultClient.Do(req)
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	is.NoErr(err)
+	is.Equal(body, []byte(`{"a": 1, "b": 2}`))
+}
+
+func TestMatchURLPathTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		template string
+		path     string
+		status   int
+		body     string
+	}{
+		{name: "Parameters", template: "/users/{id}/orders/{orderId}", path: "/users/123/orders/abc",
+			status: http.StatusOK, body: "123/abc"},
+		{name: "Escaped", template: "/users/{id}/orders/{orderId}", path: "/users/a%20b/orders/abc",
+			status: http.StatusOK, body: "a b/abc"},
+		{name: "Missing segment", template: "/users/{id}/orders/{orderId}", path: "/users/123/orders",
+			status: http.StatusNotFound},
+		{name: "Extra segment", template: "/users/{id}/orders/{orderId}", path: "/users/123/orders/abc/def",
+			status: http.StatusNotFound},
+		{name: "Empty parameter", template: "/users/{id}/orders/{orderId}", path: "/users//orders/abc",
+			status: http.StatusNotFound},
+		{name: "Pattern matches", template: "/users/{id:[0-9]+}/orders/{orderId}", path: "/users/123/orders/abc",
+			status: http.StatusOK, body: "123/abc"},
+		{name: "Pattern doesn't match", template: "/users/{id:[0-9]+}/orders/{orderId}",
+			path: "/users/abc/orders/abc", status: http.StatusNotFound},
+		{name: "Wildcard", template: "/users/{id}/*", path: "/users/123/orders/abc",
+			status: http.StatusOK, body: "123/orders/abc"},
+		{name: "Empty wildcard", template: "/users/{id}/*", path: "/users/123",
+			status: http.StatusOK, body: "123/"},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
+			defer server.Close()
+
+			server.Matches(gomockserver.MatchURLPathTemplate(tt.template)).
+				RespondsWith(gomockserver.ResponseBuilderFunc(func(r *gomockserver.Response, req *http.Request) {
+					params := gomockserver.PathParams(req)
+					r.Body = []byte(params["id"] + "/" + params["orderId"] + params[gomockserver.PathWildcard])
+				}))
+
+			resp := makeRequest(t, http.MethodGet, server.URL()+tt.path)
+			defer resp.Body.Close()
+
+			is.Equal(resp.StatusCode, tt.status)
+
+			if tt.status == http.StatusOK {
+				body, err := ioutil.ReadAll(resp.Body)
+				is.NoErr(err)
+				is.Equal(string(body), tt.body)
+			}
+		})
+	}
+}
+
+func TestMatchURLPathTemplateParamsNotShared(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPathTemplate("/users/{other}"), gomockserver.MatchMethod(http.MethodPost))
+	server.Matches(gomockserver.MatchURLPathTemplate("/users/{id}")).
+		RespondsWith(gomockserver.ResponseBuilderFunc(func(r *gomockserver.Response, req *http.Request) {
+			r.Body = []byte(fmt.Sprintf("%v", gomockserver.PathParams(req)))
+		}))
+
+	resp := makeRequest(t, http.MethodGet, fmt.Sprintf("%s/users/123", server.URL()))
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	is.NoErr(err)
+	is.Equal(string(body), "map[id:123]")
+}
+
+func TestPatternMatchers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rule    gomockserver.MatchRule
+		path    string
+		headers map[string]string
+		status  int
+	}{
+		{name: "Path regex matches", rule: gomockserver.MatchURLPathRegex(`^/users/\d+$`), path: "/users/123",
+			status: http.StatusOK},
+		{name: "Path regex doesn't match", rule: gomockserver.MatchURLPathRegex(`^/users/\d+$`), path: "/users/abc",
+			status: http.StatusNotFound},
+		{name: "Path glob matches", rule: gomockserver.MatchURLPathGlob("/users/*/orders"),
+			path: "/users/123/orders", status: http.StatusOK},
+		{name: "Path glob doesn't cross segments", rule: gomockserver.MatchURLPathGlob("/users/*"),
+			path: "/users/123/orders", status: http.StatusNotFound},
+		{name: "Path double glob crosses segments", rule: gomockserver.MatchURLPathGlob("/users/**"),
+			path: "/users/123/orders", status: http.StatusOK},
+		{name: "Path glob single character", rule: gomockserver.MatchURLPathGlob("/users/?"),
+			path: "/users/1", status: http.StatusOK},
+		{name: "Header regex matches", rule: gomockserver.MatchHeaderRegex("Authorization", `^Bearer .+$`),
+			headers: map[string]string{"Authorization": "Bearer abc"}, status: http.StatusOK},
+		{name: "Header regex doesn't match", rule: gomockserver.MatchHeaderRegex("Authorization", `^Bearer .+$`),
+			headers: map[string]string{"Authorization": "Basic abc"}, status: http.StatusNotFound},
+		{name: "Header glob matches", rule: gomockserver.MatchHeaderGlob("Authorization", "Bearer *"),
+			headers: map[string]string{"Authorization": "Bearer a/b.c"}, status: http.StatusOK},
+		{name: "Header glob doesn't match", rule: gomockserver.MatchHeaderGlob("Authorization", "Bearer *"),
+			headers: map[string]string{"Authorization": "Basic abc"}, status: http.StatusNotFound},
+		{name: "Header present", rule: gomockserver.MatchHeaderPresent("X-Test"),
+			headers: map[string]string{"X-Test": ""}, status: http.StatusOK},
+		{name: "Header not present", rule: gomockserver.MatchHeaderPresent("X-Test"), status: http.StatusNotFound},
+		{name: "Header absent", rule: gomockserver.MatchHeaderAbsent("X-Test"), status: http.StatusOK},
+		{name: "Header not absent", rule: gomockserver.MatchHeaderAbsent("X-Test"),
+			headers: map[string]string{"X-Test": "1"}, status: http.StatusNotFound},
+		{name: "Query regex matches", rule: gomockserver.MatchURLQueryRegex("id", `^\d+$`), path: "/?id=123",
+			status: http.StatusOK},
+		{name: "Query regex doesn't match", rule: gomockserver.MatchURLQueryRegex("id", `^\d+$`), path: "/?id=abc",
+			status: http.StatusNotFound},
+		{name: "Query glob matches", rule: gomockserver.MatchURLQueryGlob("name", "a*c"), path: "/?name=abbc",
+			status: http.StatusOK},
+		{name: "Query glob doesn't match", rule: gomockserver.MatchURLQueryGlob("name", "a*c"), path: "/?name=abcd",
+			status: http.StatusNotFound},
+		{name: "Query present", rule: gomockserver.MatchURLQueryPresent("debug"), path: "/?debug",
+			status: http.StatusOK},
+		{name: "Query not present", rule: gomockserver.MatchURLQueryPresent("debug"), path: "/",
+			status: http.StatusNotFound},
+		{name: "Query absent", rule: gomockserver.MatchURLQueryAbsent("debug"), path: "/?other=1",
+			status: http.StatusOK},
+		{name: "Query not absent", rule: gomockserver.MatchURLQueryAbsent("debug"), path: "/?debug=true",
+			status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
+			defer server.Close()
+
+			server.Matches(tt.rule)
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL()+tt.path, nil)
+			is.NoErr(err)
+
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			is.NoErr(err)
+
+			defer resp.Body.Close()
+
+			is.Equal(resp.StatusCode, tt.status)
+		})
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	t.Parallel()
+
+	rule := gomockserver.MatchAll(
+		gomockserver.MatchAny(gomockserver.MatchMethod(http.MethodPost), gomockserver.MatchMethod(http.MethodPut)),
+		gomockserver.MatchURLPath("/items"),
+		gomockserver.MatchNot(gomockserver.MatchHeaderPresent("X-Dry-Run")),
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		dryRun bool
+		status int
+	}{
+		{name: "POST", method: http.MethodPost, path: "/items", status: http.StatusOK},
+		{name: "PUT", method: http.MethodPut, path: "/items", status: http.StatusOK},
+		{name: "GET", method: http.MethodGet, path: "/items", status: http.StatusNotFound},
+		{name: "Wrong path", method: http.MethodPost, path: "/other", status: http.StatusNotFound},
+		{name: "Dry run", method: http.MethodPost, path: "/items", dryRun: true, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
+			defer server.Close()
+
+			server.Matches(rule)
+
+			req, err := http.NewRequestWithContext(context.Background(), tt.method, server.URL()+tt.path, nil)
+			is.NoErr(err)
+
+			if tt.dryRun {
+				req.Header.Set("X-Dry-Run", "true")
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			is.NoErr(err)
+
+			defer resp.Body.Close()
+
+			is.Equal(resp.StatusCode, tt.status)
+		})
+	}
+}
+
+func TestMatchAnyJSON(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	match := server.Matches(gomockserver.MatchAny(
+		gomockserver.MatchJSONFull(map[string]interface{}{"a": 2}),
+		gomockserver.MatchJSONFull(map[string]interface{}{"a": 1}),
+	))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL(),
+		bytes.NewReader([]byte(`{"a": 1}`)))
+	is.NoErr(err)
+
+	resp, err := http.DefaultClient.Do(req)
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(match.Count(), 1)
+}
+
+func TestUnmatchedRequestClosestMatches(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	reporter := &recordingReporter{}
+
+	server, err := gomockserver.NewServer(reporter, gomockserver.WithAllowUnmatchedRequests())
+	is.NoErr(err)
+
+	server.Matches(gomockserver.MatchMethod(http.MethodPost), gomockserver.MatchURLPath("/items"))
+	server.Matches(gomockserver.MatchMethod(http.MethodGet), gomockserver.MatchURLPath("/other"),
+		gomockserver.MatchHeader("X-Test", "1"))
+	server.Matches(gomockserver.MatchMethod(http.MethodGet), gomockserver.MatchURLPath("/items"),
+		gomockserver.MatchHeader("X-Test", "1"))
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/items")
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusNotFound)
+
+	server.Verify()
+
+	is.Equal(len(reporter.errors), 0)
+	is.Equal(len(reporter.logs), 1)
+
+	parts := strings.SplitN(reporter.logs[0], "\n\nClosest matches:\n", 2)
+	is.Equal(len(parts), 2)
+	is.True(strings.HasPrefix(parts[0], "Unmatched request: GET /items"))
+	is.Equal(strings.Split(parts[1], "\n"), []string{
+		"Match 3 (2 of 3 rules passed):",
+		"    PASS: method: expected GET, got GET",
+		"    PASS: URL path: expected /items, got /items",
+		"    FAIL: header X-Test: expected 1, got []",
+		"Match 1 (1 of 2 rules passed):",
+		"    FAIL: method: expected POST, got GET",
+		"    PASS: URL path: expected /items, got /items",
+		"Match 2 (1 of 3 rules passed):",
+		"    PASS: method: expected GET, got GET",
+		"    FAIL: URL path: expected /other, got /items",
+		"    FAIL: header X-Test: expected 1, got []",
+	})
+}
+
+func TestExplainRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rule    gomockserver.MatchRule
+		matched bool
+		reason  string
+	}{
+		{name: "Method", rule: gomockserver.MatchMethod(http.MethodPost), matched: false,
+			reason: "method: expected POST, got GET"},
+		{name: "Header", rule: gomockserver.MatchHeader("X-Test", "2"), matched: false,
+			reason: "header X-Test: expected 2, got [1]"},
+		{name: "Query", rule: gomockserver.MatchURLQuery("answer", "42"), matched: true,
+			reason: "query parameter answer: expected 42, got [42]"},
+		{name: "Request", rule: gomockserver.MatchRequest(http.MethodGet, "/other"), matched: false,
+			reason: "all of (PASS: method: expected GET, got GET; FAIL: URL path: expected /other, got /testing)"},
+		{name: "Not", rule: gomockserver.MatchNot(gomockserver.MatchHeaderPresent("X-Test")), matched: false,
+			reason: "not (header X-Test: expected any value, got [1])"},
+		{name: "Any", rule: gomockserver.MatchAny(gomockserver.MatchURLPathGlob("/test*"),
+			gomockserver.MatchURLPathRegex("^/other")), matched: true,
+			reason: "any of (PASS: URL path: expected to match glob /test*, got /testing; " +
+				"FAIL: URL path: expected to match regex ^/other, got /testing)"},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/testing?answer=42", nil)
+			req.Header.Set("X-Test", "1")
+
+			explainer, ok := tt.rule.(gomockserver.MatchRuleExplainer)
+			is.True(ok)
+
+			matched, reason := explainer.Explain(req)
+			is.Equal(matched, tt.matched)
+			is.Equal(reason, tt.reason)
+			is.Equal(tt.rule.Matches(req), tt.matched)
+		})
+	}
+}
+
+func TestExpectationsMet(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+
+	times := server.Matches(gomockserver.MatchURLPath("/times")).Times(2)
+	atLeast := server.Matches(gomockserver.MatchURLPath("/at-least")).AtLeast(1)
+	atMost := server.Matches(gomockserver.MatchURLPath("/at-most")).AtMost(1)
+	between := server.Matches(gomockserver.MatchURLPath("/between")).AtLeast(1).AtMost(2)
+	never := server.Matches(gomockserver.MatchURLPath("/never")).Never()
+
+	for _, path := range []string{"/times", "/times", "/at-least", "/at-least", "/at-least", "/between"} {
+		resp := makeRequest(t, http.MethodGet, server.URL()+path)
+		resp.Body.Close()
+
+		is.Equal(resp.StatusCode, http.StatusOK)
+	}
+
+	is.Equal(times.Count(), 2)
+	is.Equal(atLeast.Count(), 3)
+	is.Equal(atMost.Count(), 0)
+	is.Equal(between.Count(), 1)
+	is.Equal(never.Count(), 0)
+}
+
+func TestRequestJournal(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
+	defer server.Close()
+
+	match := server.Matches(gomockserver.MatchRequest(http.MethodPost, "/items"))
+
+	for _, body := range []string{`{"name": "first"}`, `{"name": "second"}`} {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL()+"/items?page=1",
+			bytes.NewReader([]byte(body)))
+		is.NoErr(err)
+
+		req.Header.Set("X-Test", "1")
+
+		resp, err := http.DefaultClient.Do(req)
+		is.NoErr(err)
+		resp.Body.Close()
+	}
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/other")
+	resp.Body.Close()
+
+	requests := server.Requests()
+	is.Equal(len(requests), 3)
+	is.Equal(requests[0].Method, http.MethodPost)
+	is.Equal(requests[0].URL.Path, "/items")
+	is.Equal(requests[0].URL.Query().Get("page"), "1")
+	is.Equal(requests[0].Header.Get("X-Test"), "1")
+	is.Equal(requests[0].Match, match)
+	is.True(!requests[0].Timestamp.IsZero())
+
+	is.Equal(len(match.Requests()), 2)
+
+	var body map[string]string
+	is.NoErr(match.Requests().Last().DecodeJSON(&body))
+	is.Equal(body["name"], "second")
+
+	is.Equal(len(requests.Matching(gomockserver.MatchJSONCompatible(map[string]string{"name": "first"}))), 1)
+	is.Equal(len(requests.Matching(gomockserver.MatchMethod(http.MethodDelete))), 0)
+	is.Equal(requests.Matching(gomockserver.MatchMethod(http.MethodDelete)).First(), nil)
+
+	unmatched := server.UnmatchedRequests()
+	is.Equal(len(unmatched), 1)
+	is.Equal(unmatched[0].String(), "GET /other")
+	is.Equal(unmatched[0].Match, nil)
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	match := server.Matches(gomockserver.MatchURLPath("/concurrent"))
+
+	const (
+		clients  = 20
+		requests = 10
+	)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			server.Matches(gomockserver.MatchURLPath(fmt.Sprintf("/client/%d", i))).
+				RespondsWith(gomockserver.ResponseStatus(http.StatusAccepted)).
+				Times(requests)
+
+			for j := 0; j < requests; j++ {
+				resp := makeRequest(t, http.MethodGet, server.URL()+"/concurrent")
+				resp.Body.Close()
+
+				resp = makeRequest(t, http.MethodGet, fmt.Sprintf("%s/client/%d", server.URL(), i))
+				resp.Body.Close()
+
+				_ = match.Count()
+				_ = server.Requests()
+				_ = server.UnmatchedCount()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	is.Equal(match.Count(), clients*requests)
+	is.Equal(len(match.Requests()), clients*requests)
+	is.Equal(len(server.Requests()), 2*clients*requests)
+	is.Equal(server.UnmatchedCount(), 0)
+}
+
+func TestCloseDuringResponseUsingURL(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	started := make(chan struct{})
+
+	server.Matches(gomockserver.MatchURLPath("/create")).
+		RespondsWith(gomockserver.ResponseBuilderFunc(func(r *gomockserver.Response, req *http.Request) {
+			close(started)
+			time.Sleep(200 * time.Millisecond)
+
+			r.Headers.Set("Location", server.URL()+"/created")
+		}))
+
+	responses := make(chan *http.Response, 1)
+
+	go func() {
+		resp := makeRequest(t, http.MethodGet, server.URL()+"/create")
+		resp.Body.Close()
+
+		responses <- resp
+	}()
+
+	<-started
+
+	closed := make(chan struct{})
+
+	go func() {
+		server.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return while a response was being built")
+	}
+
+	resp := <-responses
+	is.True(strings.HasSuffix(resp.Header.Get("Location"), "/created"))
+}
+
+func TestResponseSequence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mode     gomockserver.SequenceMode
+		expected []int
+	}{
+		{name: "Repeat last", mode: gomockserver.SequenceRepeatLast,
+			expected: []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK, http.StatusOK}},
+		{name: "Cycle", mode: gomockserver.SequenceCycle,
+			expected: []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusServiceUnavailable, http.StatusOK}},
+		{name: "Fall through", mode: gomockserver.SequenceFallThrough,
+			expected: []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusNotFound, http.StatusNotFound}},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t)
+			defer server.Close()
+
+			match := server.Matches(gomockserver.MatchURLPath("/retry")).
+				RespondsWith(gomockserver.ResponseSetHeader("X-Test", "1")).
+				RespondsWithSequence(
+					gomockserver.ResponseStatus(http.StatusServiceUnavailable),
+					gomockserver.ResponseStatus(http.StatusOK),
+				).
+				WhenSequenceExhausted(tt.mode)
+			fallback := server.Matches(gomockserver.MatchURLPath("/retry")).
+				RespondsWith(gomockserver.ResponseStatus(http.StatusNotFound))
+
+			for _, expected := range tt.expected {
+				resp := makeRequest(t, http.MethodGet, server.URL()+"/retry")
+				resp.Body.Close()
+
+				is.Equal(resp.StatusCode, expected)
+
+				if expected != http.StatusNotFound {
+					is.Equal(resp.Header.Get("X-Test"), "1")
+				}
+			}
+
+			is.Equal(match.Count()+fallback.Count(), len(tt.expected))
+		})
+	}
+}
+
+func TestScenario(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchRequest(http.MethodPost, "/orders")).
+		InScenario("orders").
+		WillSetScenarioStateTo("created").
+		RespondsWith(gomockserver.ResponseStatus(http.StatusCreated))
+	server.Matches(gomockserver.MatchRequest(http.MethodGet, "/orders/1")).
+		InScenario("orders").
+		WhenScenarioStateIs("created").
+		RespondsWith(gomockserver.ResponseJSON("Order"))
+	server.Matches(gomockserver.MatchRequest(http.MethodDelete, "/orders/1")).
+		InScenario("orders").
+		WhenScenarioStateIs("created").
+		WillSetScenarioStateTo("deleted").
+		RespondsWith(gomockserver.ResponseStatus(http.StatusNoContent))
+	server.Matches(gomockserver.MatchRequest(http.MethodGet, "/orders/1")).
+		RespondsWith(gomockserver.ResponseStatus(http.StatusNotFound))
+
+	steps := []struct {
+		method string
+		path   string
+		status int
+		state  string
+	}{
+		{method: http.MethodGet, path: "/orders/1", status: http.StatusNotFound, state: gomockserver.ScenarioStarted},
+		{method: http.MethodPost, path: "/orders", status: http.StatusCreated, state: "created"},
+		{method: http.MethodGet, path: "/orders/1", status: http.StatusOK, state: "created"},
+		{method: http.MethodDelete, path: "/orders/1", status: http.StatusNoContent, state: "deleted"},
+		{method: http.MethodGet, path: "/orders/1", status: http.StatusNotFound, state: "deleted"},
+	}
+
+	for _, step := range steps {
+		resp := makeRequest(t, step.method, server.URL()+step.path)
+		resp.Body.Close()
+
+		is.Equal(resp.StatusCode, step.status)
+		is.Equal(server.ScenarioState("orders"), step.state)
+	}
+
+	server.SetScenarioState("orders", "created")
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/orders/1")
+	resp.Body.Close()
+	is.Equal(resp.StatusCode, http.StatusOK)
+
+	server.ResetScenarios("orders")
+	is.Equal(server.ScenarioState("orders"), gomockserver.ScenarioStarted)
+}
+
+func TestResponseDelay(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPath("/slow")).
+		RespondsWith(gomockserver.ResponseDelay(100 * time.Millisecond))
+
+	start := time.Now()
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/slow")
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.True(time.Since(start) >= 100*time.Millisecond)
+}
+
+func TestResponseDelayClientTimeout(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+
+	server.Matches(gomockserver.MatchURLPath("/slow")).
+		RespondsWith(gomockserver.ResponseRandomDelay(time.Minute, 2*time.Minute))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL()+"/slow", nil)
+	is.NoErr(err)
+
+	_, err = http.DefaultClient.Do(req) //nolint:bodyclose
+	is.True(errors.Is(err, context.DeadlineExceeded))
+
+	start := time.Now()
+
+	server.Close()
+	is.True(time.Since(start) < time.Second)
+}
+
+func TestResponseChunkDelay(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPath("/slow")).
+		RespondsWith(gomockserver.ResponseBody([]byte("abcdef")),
+			gomockserver.ResponseChunkDelay(2, gomockserver.FixedDelay(20*time.Millisecond)))
+
+	start := time.Now()
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/slow")
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	is.NoErr(err)
+	is.Equal(string(body), "abcdef")
+	is.True(time.Since(start) >= 40*time.Millisecond)
+}
+
+func TestDelayDistributions(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	uniform := gomockserver.UniformDelay(10*time.Millisecond, 20*time.Millisecond)
+	logNormal := gomockserver.LogNormalDelay(100*time.Millisecond, time.Second)
+
+	const samples = 1001
+
+	logNormalSamples := make([]time.Duration, 0, samples)
+
+	for i := 0; i < samples; i++ {
+		delay := uniform.Sample()
+		is.True(delay >= 10*time.Millisecond && delay < 20*time.Millisecond)
+
+		logNormalSamples = append(logNormalSamples, logNormal.Sample())
+	}
+
+	sort.Slice(logNormalSamples, func(i, j int) bool {
+		return logNormalSamples[i] < logNormalSamples[j]
+	})
+
+	median := logNormalSamples[samples/2]
+	is.True(median > 80*time.Millisecond && median < 120*time.Millisecond)
+}
+
+func TestResponseFaults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		fault gomockserver.Fault
+		check func(t *testing.T, resp *http.Response, body []byte, err error)
+	}{
+		{
+			name:  "Close connection",
+			fault: gomockserver.FaultCloseConnection,
+			check: func(t *testing.T, resp *http.Response, body []byte, err error) {
+				t.Helper()
+				is := is.New(t)
+
+				is.True(resp == nil)
+				is.True(errors.Is(err, io.EOF))
+			},
+		},
+		{
+			name:  "Connection reset",
+			fault: gomockserver.FaultConnectionReset,
+			check: func(t *testing.T, resp *http.Response, body []byte, err error) {
+				t.Helper()
+				is := is.New(t)
+
+				is.True(errors.Is(err, syscall.ECONNRESET))
+			},
+		},
+		{
+			name:  "Content length mismatch",
+			fault: gomockserver.FaultContentLengthMismatch,
+			check: func(t *testing.T, resp *http.Response, body []byte, err error) {
+				t.Helper()
+				is := is.New(t)
+
+				is.True(resp != nil)
+				is.Equal(resp.StatusCode, http.StatusOK)
+				is.Equal(resp.ContentLength, int64(25))
+				is.Equal(string(body), "Hello, World")
+				is.True(errors.Is(err, io.ErrUnexpectedEOF))
+			},
+		},
+		{
+			name:  "Malformed response",
+			fault: gomockserver.FaultMalformedResponse,
+			check: func(t *testing.T, resp *http.Response, body []byte, err error) {
+				t.Helper()
+				is := is.New(t)
+
+				is.True(resp == nil)
+				is.True(err != nil)
+				is.True(strings.Contains(err.Error(), "malformed HTTP"))
+			},
+		},
+		{
+			name:  "Stall",
+			fault: gomockserver.FaultStall,
+			check: func(t *testing.T, resp *http.Response, body []byte, err error) {
+				t.Helper()
+				is := is.New(t)
+
+				is.True(resp == nil)
+				is.True(errors.Is(err, context.DeadlineExceeded))
+			},
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t)
+			defer server.Close()
+
+			server.Matches(gomockserver.MatchURLPath("/fault")).
+				RespondsWith(gomockserver.ResponseBody([]byte("Hello, World")),
+					gomockserver.ResponseFault(tt.fault))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL()+"/fault", nil)
+			is.NoErr(err)
+
+			var body []byte
+
+			resp, err := http.DefaultClient.Do(req)
+			if err == nil {
+				defer resp.Body.Close()
+
+				body, err = ioutil.ReadAll(resp.Body)
+			}
+
+			if !errors.Is(err, context.DeadlineExceeded) {
+				is.NoErr(ctx.Err()) // Only the stalled response should wait for the client to give up.
+			}
+
+			tt.check(t, resp, body, err)
+		})
+	}
+}
+
+func TestResponseFaultStallServerClose(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+
+	server.Matches(gomockserver.MatchURLPath("/fault")).
+		RespondsWith(gomockserver.ResponseFault(gomockserver.FaultStall))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		server.Close()
+	}()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL()+"/fault", nil)
+	is.NoErr(err)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+
+	is.True(err != nil)
+}
+
+func TestStreamedResponses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		builder gomockserver.ResponseBuilder
+	}{
+		{name: "Chunks", builder: gomockserver.ResponseStreamChunks([]byte("Hello"), []byte(", "), []byte("World"))},
+		{name: "Reader", builder: gomockserver.ResponseStreamReader(func() io.Reader {
+			return strings.NewReader("Hello, World")
+		}, 5)},
+		{name: "Reader with default chunk size", builder: gomockserver.ResponseStreamReader(func() io.Reader {
+			return strings.NewReader("Hello, World")
+		}, 0)},
+		{name: "Generator", builder: gomockserver.ResponseStreamGenerator(func(index int) ([]byte, error) {
+			chunks := []string{"Hello", ", ", "World"}
+			if index >= len(chunks) {
+				return nil, io.EOF
+			}
+
+			return []byte(chunks[index]), nil
+		})},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t)
+			defer server.Close()
+
+			server.Matches(gomockserver.MatchURLPath("/stream")).
+				RespondsWith(tt.builder, gomockserver.ResponseStreamDelay(gomockserver.FixedDelay(time.Millisecond)))
+
+			for i := 0; i < 2; i++ {
+				resp := makeRequest(t, http.MethodGet, server.URL()+"/stream")
+				defer resp.Body.Close()
+
+				is.Equal(resp.TransferEncoding, []string{"chunked"})
+
+				body, err := ioutil.ReadAll(resp.Body)
+				is.NoErr(err)
+				is.Equal(string(body), "Hello, World")
+			}
+		})
+	}
+}
+
+func TestStreamedResponseIsIncremental(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	release := make(chan struct{})
+
+	server.Matches(gomockserver.MatchURLPath("/stream")).
+		RespondsWith(gomockserver.ResponseStreamGenerator(func(index int) ([]byte, error) {
+			switch index {
+			case 0:
+				return []byte("first"), nil
+			case 1:
+				<-release
+
+				return []byte("second"), nil
+			default:
+				return nil, io.EOF
+			}
+		}))
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/stream")
+	defer resp.Body.Close()
+
+	chunk := make([]byte, 5)
+	_, err := io.ReadFull(resp.Body, chunk)
+	is.NoErr(err)
+	is.Equal(string(chunk), "first")
+
+	close(release)
+
+	rest, err := ioutil.ReadAll(resp.Body)
+	is.NoErr(err)
+	is.Equal(string(rest), "second")
+}
+
+func TestResponseSSE(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPath("/events")).
+		RespondsWith(gomockserver.ResponseSSE(
+			gomockserver.SSEEvent{ID: "1", Event: "greeting", Data: "Hello", Retry: time.Second},
+			gomockserver.SSEEvent{Data: "Multiple\nLines", Delay: 10 * time.Millisecond},
+		))
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/events")
+	defer resp.Body.Close()
+
+	is.Equal(resp.Header.Get("Content-Type"), "text/event-stream")
+
+	body, err := ioutil.ReadAll(resp.Body)
+	is.NoErr(err)
+	is.Equal(string(body), "id: 1\nevent: greeting\nretry: 1000\ndata: Hello\n\ndata: Multiple\ndata: Lines\n\n")
+}
+
+func TestResponseSSEPush(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+
+	stream := server.Matches(gomockserver.MatchURLPath("/events")).
+		RespondsWithSSE(gomockserver.SSEEvent{Data: "first"}).
+		KeepOpen()
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/events")
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+
+	readEvent := func() string {
+		event := ""
+
+		for {
+			line, err := reader.ReadString('\n')
+			is.NoErr(err)
+
+			if line == "\n" {
+				return event
+			}
+
+			event += line
+		}
+	}
+
+	is.Equal(readEvent(), "data: first\n")
+	is.Equal(stream.Connected(), 1)
+
+	stream.Send(gomockserver.SSEEvent{Event: "update", Data: "second"})
+	is.Equal(readEvent(), "event: update\ndata: second\n")
+
+	server.Close()
+
+	_, err := ioutil.ReadAll(reader)
+	is.NoErr(err)
+	is.Equal(stream.Connected(), 0)
+}
+
+func TestWebSocketConversation(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	ws := server.WebSocket(gomockserver.MatchURLPath("/ws")).
+		Expect(gomockserver.MatchJSONCompatible(map[string]string{"type": "hello"})).
+		SendJSON(map[string]string{"type": "welcome"}).
+		SendText("second").
+		Close(websocket.CloseNormalClosure, "bye")
+
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL(), "http")+"/ws", nil)
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+	defer conn.Close()
+
+	is.NoErr(conn.WriteMessage(websocket.TextMessage, []byte(`{"type": "hello", "name": "test"}`)))
+
+	_, message, err := conn.ReadMessage()
+	is.NoErr(err)
+	is.Equal(string(message), `{"type":"welcome"}`)
+
+	_, message, err = conn.ReadMessage()
+	is.NoErr(err)
+	is.Equal(string(message), "second")
+
+	_, _, err = conn.ReadMessage()
+
+	var closeErr *websocket.CloseError
+	is.True(errors.As(err, &closeErr))
+	is.Equal(closeErr.Code, websocket.CloseNormalClosure)
+	is.Equal(closeErr.Text, "bye")
+
+	is.Equal(ws.Match().Count(), 1)
+
+	frames := ws.Frames()
+	is.True(len(frames) >= 4)
+	is.Equal(frames[0].Direction, gomockserver.WebSocketReceived)
+	is.Equal(string(frames[0].Data), `{"type": "hello", "name": "test"}`)
+	is.Equal(frames[1].Direction, gomockserver.WebSocketSent)
+	is.Equal(frames[1].Type, gomockserver.WebSocketText)
+	is.Equal(frames[3].Type, gomockserver.WebSocketClose)
+}
+
+func TestWebSocketExpectAnyMessage(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	ws := server.WebSocket(gomockserver.MatchURLPath("/ws")).
+		Expect().
+		SendText("reply")
+
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL(), "http")+"/ws", nil)
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+	defer conn.Close()
+
+	is.NoErr(conn.WriteMessage(websocket.TextMessage, []byte("anything at all")))
+
+	_, message, err := conn.ReadMessage()
+	is.NoErr(err)
+	is.Equal(string(message), "reply")
+
+	frames := ws.Frames()
+	is.Equal(len(frames), 2)
+	is.Equal(frames[0].Direction, gomockserver.WebSocketReceived)
+	is.Equal(frames[1].Direction, gomockserver.WebSocketSent)
+	is.Equal(frames[1].Type, gomockserver.WebSocketText)
+}
+
+func TestWebSocketPush(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+
+	ws := server.WebSocket(gomockserver.MatchURLPath("/ws")).
+		SendText("connected")
+
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL(), "http")+"/ws", nil)
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+	defer conn.Close()
+
+	_, message, err := conn.ReadMessage()
+	is.NoErr(err)
+	is.Equal(string(message), "connected")
+	is.Equal(ws.Connected(), 1)
+
+	ws.PushJSON(map[string]int{"count": 1})
+
+	_, message, err = conn.ReadMessage()
+	is.NoErr(err)
+	is.Equal(string(message), `{"count":1}`)
+
+	server.Close()
+
+	_, _, err = conn.ReadMessage()
+	is.True(err != nil)
+}
+
+func TestWebSocketNotUpgrade(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
+	defer server.Close()
+
+	ws := server.WebSocket(gomockserver.MatchURLPath("/ws"))
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/ws")
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusNotFound)
+	is.Equal(ws.Match().Count(), 0)
+}
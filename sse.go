@@ -0,0 +1,197 @@
+package gomockserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSEEvent represents a single Server-Sent Event to send to the client.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+	// Delay is how long to wait before sending this event.
+	Delay time.Duration
+}
+
+// encode will produce the wire format of this event.
+func (e SSEEvent) encode() []byte {
+	var b bytes.Buffer
+
+	if e.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", e.ID)
+	}
+
+	if e.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", e.Event)
+	}
+
+	if e.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", e.Retry.Milliseconds())
+	}
+
+	for _, line := range strings.Split(e.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+
+	b.WriteString("\n")
+
+	return b.Bytes()
+}
+
+// SSEStream is a `ResponseBuilder` that responds with a stream of Server-Sent Events.
+// Every client is sent the scripted events that the stream was created with, and can then optionally be kept
+// connected so that the test can push further events to it.
+type SSEStream struct {
+	mutex    sync.Mutex
+	events   []SSEEvent
+	keepOpen bool
+	clients  map[*sseClient]struct{}
+}
+
+// NewSSEStream creates a new stream of Server-Sent Events, sending the provided events to every client in order.
+func NewSSEStream(events ...SSEEvent) *SSEStream {
+	return &SSEStream{
+		events:  events,
+		clients: map[*sseClient]struct{}{},
+	}
+}
+
+// ResponseSSE returns a `ResponseBuilder` that responds with the provided Server-Sent Events, and then ends the
+// response. Use `Match.RespondsWithSSE` instead to push further events to clients from the test.
+func ResponseSSE(events ...SSEEvent) ResponseBuilder {
+	return NewSSEStream(events...)
+}
+
+// RespondsWithSSE registers a response of the provided Server-Sent Events, returning the stream so that the test can
+// push further events to connected clients.
+func (m *Match) RespondsWithSSE(events ...SSEEvent) *SSEStream {
+	stream := NewSSEStream(events...)
+
+	m.RespondsWith(stream)
+
+	return stream
+}
+
+// KeepOpen will keep clients connected once they have been sent the scripted events, so that further events can be
+// pushed to them with `Send`. The connections are closed when the client goes away or the server is closed.
+func (s *SSEStream) KeepOpen() *SSEStream {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keepOpen = true
+
+	return s
+}
+
+// Send will push the provided events to every currently connected client, after any scripted events that they have
+// not yet been sent.
+func (s *SSEStream) Send(events ...SSEEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for client := range s.clients {
+		client.push(events...)
+	}
+}
+
+// Connected returns the number of clients that are currently connected to this stream.
+func (s *SSEStream) Connected() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.clients)
+}
+
+func (s *SSEStream) PopulateResponse(r *Response, req *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	client := &sseClient{
+		stream:   s,
+		keepOpen: s.keepOpen,
+		notify:   make(chan struct{}, 1),
+	}
+	client.push(s.events...)
+
+	s.clients[client] = struct{}{}
+
+	r.Headers.Set("Content-Type", "text/event-stream")
+	r.Headers.Set("Cache-Control", "no-cache")
+	r.Stream = client
+}
+
+// sseClient is the `BodyStream` for a single client connected to an `SSEStream`.
+type sseClient struct {
+	stream   *SSEStream
+	keepOpen bool
+	mutex    sync.Mutex
+	pending  []SSEEvent
+	notify   chan struct{}
+}
+
+// push will queue up the provided events to be sent to the client.
+func (c *sseClient) push(events ...SSEEvent) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.pending = append(c.pending, events...)
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the next queued event, if there is one.
+func (c *sseClient) next() (SSEEvent, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.pending) == 0 {
+		return SSEEvent{}, false
+	}
+
+	event := c.pending[0]
+	c.pending = c.pending[1:]
+
+	return event, true
+}
+
+func (c *sseClient) NextChunk(ctx context.Context) ([]byte, error) {
+	for {
+		if event, ok := c.next(); ok {
+			if !sleepContext(ctx, event.Delay) {
+				return nil, io.EOF
+			}
+
+			return event.encode(), nil
+		}
+
+		if !c.keepOpen {
+			return nil, io.EOF
+		}
+
+		select {
+		case <-c.notify:
+		case <-ctx.Done():
+			return nil, io.EOF
+		}
+	}
+}
+
+func (c *sseClient) Close() error {
+	c.stream.mutex.Lock()
+	defer c.stream.mutex.Unlock()
+
+	delete(c.stream.clients, c)
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package gomockserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// DefaultStreamChunkSize is the size of the chunks read by `ResponseStreamReader` when it is given a chunk size that is
+// not positive.
+const DefaultStreamChunkSize = 32 * 1024
+
+// BodyStream represents a source of chunks for a response body that is streamed to the client.
+// Each chunk is written and flushed to the client as soon as it is produced. If the stream is also an `io.Closer` then
+// it is closed once the response is finished.
+type BodyStream interface {
+	// NextChunk returns the next chunk of the body, or `io.EOF` once there are no more chunks.
+	// The context is cancelled if the client goes away or the server is closed.
+	NextChunk(ctx context.Context) ([]byte, error)
+}
+
+// BodyStreamFunc is a function type that implements the `BodyStream` interface.
+type BodyStreamFunc func(ctx context.Context) ([]byte, error)
+
+func (f BodyStreamFunc) NextChunk(ctx context.Context) ([]byte, error) {
+	return f(ctx)
+}
+
+// chunksStream is a `BodyStream` that produces each of a fixed list of chunks in turn.
+type chunksStream struct {
+	chunks [][]byte
+}
+
+func (s *chunksStream) NextChunk(ctx context.Context) ([]byte, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+
+	return chunk, nil
+}
+
+// splitChunks will split the provided data into chunks of at most the given size.
+func splitChunks(data []byte, size int) [][]byte {
+	chunks := [][]byte{}
+
+	for start := 0; start < len(data); start += size {
+		end := start + size
+		if end > len(data) {
+			end = len(data)
+		}
+
+		chunks = append(chunks, data[start:end])
+	}
+
+	return chunks
+}
+
+// readerStream is a `BodyStream` that produces chunks by reading from an `io.Reader`.
+type readerStream struct {
+	reader    io.Reader
+	chunkSize int
+}
+
+func (s *readerStream) NextChunk(ctx context.Context) ([]byte, error) {
+	chunk := make([]byte, s.chunkSize)
+
+	n, err := io.ReadFull(s.reader, chunk)
+	if errors.Is(err, io.ErrUnexpectedEOF) || (errors.Is(err, io.EOF) && n > 0) {
+		err = nil
+	}
+
+	return chunk[:n], err
+}
+
+func (s *readerStream) Close() error {
+	if closer, ok := s.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// ResponseStreamChunks returns a `ResponseBuilder` that streams the response body to the client as the provided
+// chunks, flushing after each one.
+func ResponseStreamChunks(chunks ...[]byte) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		r.Stream = &chunksStream{chunks: chunks}
+	})
+}
+
+// ResponseStreamReader returns a `ResponseBuilder` that streams the response body to the client by reading it in
+// chunks of the given size from an `io.Reader`, flushing after each one. If the chunk size is not positive then
+// `DefaultStreamChunkSize` is used instead. The provided function is called to open a new reader for every response,
+// and if the reader is also an `io.Closer` then it is closed once the response is finished.
+func ResponseStreamReader(open func() io.Reader, chunkSize int) ResponseBuilder {
+	if chunkSize <= 0 {
+		chunkSize = DefaultStreamChunkSize
+	}
+
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		r.Stream = &readerStream{reader: open(), chunkSize: chunkSize}
+	})
+}
+
+// ResponseStreamGenerator returns a `ResponseBuilder` that streams the response body to the client using chunks
+// produced by the provided function, flushing after each one. The function is called with the index of each chunk in
+// turn, starting from zero for every response, until it returns `io.EOF`.
+func ResponseStreamGenerator(generate func(index int) ([]byte, error)) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		index := 0
+
+		r.Stream = BodyStreamFunc(func(ctx context.Context) ([]byte, error) {
+			chunk, err := generate(index)
+			index++
+
+			return chunk, err
+		})
+	})
+}
+
+// ResponseStreamDelay returns a `ResponseBuilder` that waits for a delay taken from the provided distribution between
+// each chunk of a streamed response body.
+func ResponseStreamDelay(delay DelayDistribution) ResponseBuilder {
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		r.ChunkDelay = delay
+	})
+}
+
+// writeStream will write every chunk from the stream to the client, flushing after each one and waiting between them
+// as configured by the response. If the stream fails part way through then the response is aborted, so that the
+// client does not mistake it for a complete body.
+func (r Response) writeStream(ctx context.Context, w http.ResponseWriter, stream BodyStream) {
+	for first := true; ; first = false {
+		if !first && r.ChunkDelay != nil && !sleepContext(ctx, r.ChunkDelay.Sample()) {
+			return
+		}
+
+		chunk, err := stream.NextChunk(ctx)
+		if errors.Is(err, io.EOF) {
+			return
+		} else if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+
+		if _, err := w.Write(chunk); err != nil {
+			return
+		}
+
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
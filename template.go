@@ -0,0 +1,197 @@
+package gomockserver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// TemplateData is the data that is available to response templates, describing the incoming request.
+type TemplateData struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the path of the request URL.
+	Path string
+	// PathSegments is the path of the request URL split into its segments, ignoring any leading or trailing slash.
+	PathSegments []string
+	// PathParams is every parameter that was captured from the path by `MatchURLPathTemplate`.
+	PathParams map[string]string
+	// Query is the query parameters of the request URL.
+	Query url.Values
+	// Headers is the headers of the request.
+	Headers http.Header
+	// Cookies is the value of every cookie in the request, keyed by name.
+	Cookies map[string]string
+	// Body is the body of the request.
+	Body string
+	// JSON is the body of the request parsed as JSON, or `nil` if it is not valid JSON.
+	JSON interface{}
+}
+
+// newTemplateData builds the data to render a response template with from the incoming request.
+func newTemplateData(req *http.Request) TemplateData {
+	segments := []string{}
+	if path := strings.Trim(req.URL.Path, "/"); path != "" {
+		segments = strings.Split(path, "/")
+	}
+
+	cookies := map[string]string{}
+	for _, cookie := range req.Cookies() {
+		cookies[cookie.Name] = cookie.Value
+	}
+
+	body := RequestBody(req)
+
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		parsed = nil
+	}
+
+	return TemplateData{
+		Method:       req.Method,
+		Path:         req.URL.Path,
+		PathSegments: segments,
+		PathParams:   PathParams(req),
+		Query:        req.URL.Query(),
+		Headers:      req.Header,
+		Cookies:      cookies,
+		Body:         string(body),
+		JSON:         parsed,
+	}
+}
+
+// templateConfig represents the configuration of a response template that is built up from the provided options.
+type templateConfig struct {
+	seed  int64
+	funcs template.FuncMap
+}
+
+// TemplateOption represents a configuration option to apply when creating a response template.
+type TemplateOption func(*templateConfig)
+
+// TemplateSeed sets the seed for the random values generated by the template, so that the same sequence of responses
+// is produced every time.
+func TemplateSeed(seed int64) TemplateOption {
+	return func(c *templateConfig) {
+		c.seed = seed
+	}
+}
+
+// TemplateFuncs adds extra functions that can be called from the template, in addition to the built in ones.
+func TemplateFuncs(funcs template.FuncMap) TemplateOption {
+	return func(c *templateConfig) {
+		for name, fn := range funcs {
+			c.funcs[name] = fn
+		}
+	}
+}
+
+// lockedRand is a source of random numbers that is safe for concurrent use.
+type lockedRand struct {
+	mutex sync.Mutex
+	rand  *rand.Rand
+}
+
+func (r *lockedRand) intn(n int) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rand.Intn(n)
+}
+
+func (r *lockedRand) read(p []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, _ = r.rand.Read(p)
+}
+
+// templateFuncs builds the functions that are available to every response template, using the provided source of
+// random numbers.
+func templateFuncs(random *lockedRand) template.FuncMap {
+	return template.FuncMap{
+		"uuid": func() string {
+			id := make([]byte, 16)
+			random.read(id)
+
+			id[6] = (id[6] & 0x0f) | 0x40
+			id[8] = (id[8] & 0x3f) | 0x80
+
+			return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:])
+		},
+		"now": time.Now,
+		"timestamp": func() int64 {
+			return time.Now().Unix()
+		},
+		"randomInt": func(min, max int) int {
+			if max <= min {
+				return min
+			}
+
+			return min + random.intn(max-min)
+		},
+		"base64Encode": func(value string) string {
+			return base64.StdEncoding.EncodeToString([]byte(value))
+		},
+		"base64Decode": func(value string) (string, error) {
+			decoded, err := base64.StdEncoding.DecodeString(value)
+
+			return string(decoded), err
+		},
+		"json": func(value interface{}) (string, error) {
+			encoded, err := json.Marshal(value)
+
+			return string(encoded), err
+		},
+	}
+}
+
+// ResponseTemplate will render the provided `text/template` template and use it as the response body.
+// The template is rendered with a `TemplateData` describing the incoming request, and has access to the following
+// functions in addition to the standard ones:
+//
+//	uuid                 a random version 4 UUID
+//	now                  the current time, as a time.Time
+//	timestamp            the current time, as seconds since the Unix epoch
+//	randomInt min max    a random integer that is at least min and less than max
+//	base64Encode value   the value encoded as standard base64
+//	base64Decode value   the value decoded from standard base64
+//	json value           the value encoded as JSON
+//
+// If the template fails to render then the response will be an `HTTP 500 Internal Server Error` describing the
+// failure. This will panic if the template is not valid.
+func ResponseTemplate(text string, opts ...TemplateOption) ResponseBuilder {
+	cfg := templateConfig{
+		seed:  time.Now().UnixNano(),
+		funcs: template.FuncMap{},
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	random := &lockedRand{rand: rand.New(rand.NewSource(cfg.seed))} //nolint:gosec
+
+	tmpl := template.Must(template.New("response").Funcs(templateFuncs(random)).Funcs(cfg.funcs).Parse(text))
+
+	return ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		var body bytes.Buffer
+
+		if err := tmpl.Execute(&body, newTemplateData(req)); err != nil {
+			r.Status = http.StatusInternalServerError
+			r.Body = []byte(fmt.Sprintf("Failed to render response template: %v", err))
+
+			return
+		}
+
+		r.Body = body.Bytes()
+	})
+}
@@ -0,0 +1,141 @@
+package gomockserver_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/sazzer/gomockserver"
+)
+
+// renderTemplate will make a request to a new mock server that responds with the provided template, returning the
+// response status and body.
+func renderTemplate(t *testing.T, template string, req *http.Request, opts ...gomockserver.TemplateOption) (int,
+	string) {
+	t.Helper()
+	is := is.New(t)
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPathTemplate("/users/{id}/*")).
+		RespondsWith(gomockserver.ResponseTemplate(template, opts...))
+
+	uri, err := req.URL.Parse(server.URL() + req.URL.RequestURI())
+	is.NoErr(err)
+
+	req.URL = uri
+
+	resp, err := http.DefaultClient.Do(req)
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	is.NoErr(err)
+
+	return resp.StatusCode, string(body)
+}
+
+func TestResponseTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{name: "Method", template: `{{.Method}}`, expected: "POST"},
+		{name: "Path", template: `{{.Path}}`, expected: "/users/123/orders"},
+		{name: "Path segments", template: `{{index .PathSegments 2}} of {{len .PathSegments}}`, expected: "orders of 3"},
+		{name: "Path params", template: `{{.PathParams.id}}`, expected: "123"},
+		{name: "Query", template: `{{.Query.Get "page"}}`, expected: "2"},
+		{name: "Headers", template: `{{.Headers.Get "X-Correlation-Id"}}`, expected: "abc-123"},
+		{name: "Cookies", template: `{{.Cookies.session}}`, expected: "xyz"},
+		{name: "Body", template: `{{.Body}}`, expected: `{"name":"Test","tags":["a","b"]}`},
+		{name: "JSON", template: `{{.JSON.name}} {{index .JSON.tags 1}}`, expected: "Test b"},
+		{name: "JSON function", template: `{{json .JSON.tags}}`, expected: `["a","b"]`},
+		{name: "Base64 encode", template: `{{base64Encode "Hello"}}`, expected: "SGVsbG8="},
+		{name: "Base64 decode", template: `{{base64Decode "SGVsbG8="}}`, expected: "Hello"},
+		{name: "Random int", template: `{{randomInt 5 6}}`, expected: "5"},
+		{name: "Timestamp", template: `{{if gt timestamp 0}}yes{{end}}`, expected: "yes"},
+		{name: "Now", template: `{{if gt now.Year 2000}}yes{{end}}`, expected: "yes"},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/users/123/orders?page=2",
+				strings.NewReader(`{"name":"Test","tags":["a","b"]}`))
+			is.NoErr(err)
+
+			req.Header.Set("X-Correlation-Id", "abc-123")
+			req.AddCookie(&http.Cookie{Name: "session", Value: "xyz"})
+
+			status, body := renderTemplate(t, tt.template, req)
+
+			is.Equal(status, http.StatusOK)
+			is.Equal(body, tt.expected)
+		})
+	}
+}
+
+func TestResponseTemplateSeed(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	template := `{{uuid}} {{randomInt 0 1000000}}`
+
+	render := func(seed int64) string {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/users/1/", nil)
+		is.NoErr(err)
+
+		status, body := renderTemplate(t, template, req, gomockserver.TemplateSeed(seed))
+		is.Equal(status, http.StatusOK)
+
+		return body
+	}
+
+	first := render(42)
+
+	is.True(regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12} \d+$`).
+		MatchString(first))
+	is.Equal(render(42), first)
+	is.True(render(43) != first)
+}
+
+func TestResponseTemplateFuncs(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/users/1/", nil)
+	is.NoErr(err)
+
+	status, body := renderTemplate(t, `{{lower .Method}}`, req, gomockserver.TemplateFuncs(map[string]interface{}{
+		"lower": strings.ToLower,
+	}))
+
+	is.Equal(status, http.StatusOK)
+	is.Equal(body, "get")
+}
+
+func TestResponseTemplateFailure(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/users/1/", nil)
+	is.NoErr(err)
+
+	status, body := renderTemplate(t, `{{base64Decode "!!!"}}`, req)
+
+	is.Equal(status, http.StatusInternalServerError)
+	is.True(strings.HasPrefix(body, "Failed to render response template: "))
+}
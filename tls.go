@@ -0,0 +1,133 @@
+package gomockserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+)
+
+// WithTLS will start the mock server using HTTPS, with a self-signed certificate.
+// The `MockServer.Client` and `MockServer.CertPool` methods can then be used to make requests that trust it.
+func WithTLS() Option {
+	return func(c *config) {
+		c.tls = true
+	}
+}
+
+// WithTLSCertificate will start the mock server using HTTPS, with the provided certificate.
+func WithTLSCertificate(certificate tls.Certificate) Option {
+	return func(c *config) {
+		c.tls = true
+		c.certificate = &certificate
+	}
+}
+
+// WithClientCertificates will start the mock server using HTTPS, and require every client to present a certificate
+// that is signed by one of the certificate authorities in the provided pool.
+func WithClientCertificates(pool *x509.CertPool) Option {
+	return func(c *config) {
+		c.tls = true
+		c.clientCAs = pool
+	}
+}
+
+// tlsConfig builds the TLS configuration to use for the server, or `nil` to use the defaults.
+func (c config) tlsConfig() *tls.Config {
+	if c.certificate == nil && c.clientCAs == nil {
+		return nil
+	}
+
+	tlsConfig := &tls.Config{} //nolint:gosec
+
+	if c.certificate != nil {
+		tlsConfig.Certificates = []tls.Certificate{*c.certificate}
+	}
+
+	if c.clientCAs != nil {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = c.clientCAs
+	}
+
+	return tlsConfig
+}
+
+func (s *server) Client() *http.Client {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.server == nil {
+		s.reporter.Errorf("Server has been closed")
+
+		return nil
+	}
+
+	if s.client != nil {
+		return s.client
+	}
+
+	return s.server.Client()
+}
+
+func (s *server) CertPool() *x509.CertPool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	pool := x509.NewCertPool()
+
+	if s.server == nil {
+		s.reporter.Errorf("Server has been closed")
+	} else if certificate := s.server.Certificate(); certificate != nil {
+		pool.AddCert(certificate)
+	}
+
+	return pool
+}
+
+// matchClientCertificate builds a `MatchRule` to check the certificate presented by the client, describing the
+// expected value with the provided description.
+func matchClientCertificate(expected string, matcher func(*x509.Certificate) (bool, string)) MatchRule {
+	return MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return false, fmt.Sprintf("client certificate: expected %s, got no certificate", expected)
+		}
+
+		matched, actual := matcher(r.TLS.PeerCertificates[0])
+
+		return matched, fmt.Sprintf("client certificate: expected %s, got %s", expected, actual)
+	})
+}
+
+// MatchClientCertificateSubject builds a `MatchRule` to check if the client presented a certificate with the given
+// subject common name.
+func MatchClientCertificateSubject(commonName string) MatchRule {
+	return matchClientCertificate("subject CN="+commonName, func(cert *x509.Certificate) (bool, string) {
+		return cert.Subject.CommonName == commonName, "subject " + cert.Subject.String()
+	})
+}
+
+// MatchClientCertificateSAN builds a `MatchRule` to check if the client presented a certificate with the given value
+// as one of its subject alternative names. This can be a DNS name, email address, IP address or URI.
+func MatchClientCertificateSAN(name string) MatchRule {
+	return matchClientCertificate("SAN "+name, func(cert *x509.Certificate) (bool, string) {
+		sans := []string{}
+		sans = append(sans, cert.DNSNames...)
+		sans = append(sans, cert.EmailAddresses...)
+
+		for _, ip := range cert.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+
+		for _, uri := range cert.URIs {
+			sans = append(sans, uri.String())
+		}
+
+		for _, san := range sans {
+			if san == name {
+				return true, fmt.Sprintf("SANs %v", sans)
+			}
+		}
+
+		return false, fmt.Sprintf("SANs %v", sans)
+	})
+}
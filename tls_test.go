@@ -0,0 +1,175 @@
+package gomockserver_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/sazzer/gomockserver"
+)
+
+// generateCertificate will generate a new certificate with the given template, signed by the parent certificate, or
+// self-signed if there is no parent.
+func generateCertificate(t *testing.T, template *x509.Certificate, parent *tls.Certificate) tls.Certificate {
+	t.Helper()
+	is := is.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	is.NoErr(err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parentCert := template
+	var parentKey interface{} = key
+
+	if parent != nil {
+		parentCert = parent.Leaf
+		parentKey = parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	is.NoErr(err)
+
+	leaf, err := x509.ParseCertificate(der)
+	is.NoErr(err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestTLSServer(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	server := gomockserver.New(t, gomockserver.WithTLS())
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPath("/secure"))
+
+	match, err := regexp.MatchString(`^https://127\.0\.0\.1:\d+$`, server.URL())
+	is.NoErr(err)
+	is.True(match)
+
+	resp, err := server.Client().Get(server.URL() + "/secure") //nolint:noctx
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:    server.CertPool(),
+		MinVersion: tls.VersionTLS12,
+	}}}
+
+	resp, err = client.Get(server.URL() + "/secure") //nolint:noctx
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+}
+
+func TestTLSServerCustomCertificate(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	certificate := generateCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "mock"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:        true,
+
+		BasicConstraintsValid: true,
+	}, nil)
+
+	server := gomockserver.New(t, gomockserver.WithTLSCertificate(certificate))
+	defer server.Close()
+
+	server.Matches(gomockserver.MatchURLPath("/secure"))
+
+	resp, err := server.Client().Get(server.URL() + "/secure") //nolint:noctx
+	is.NoErr(err)
+
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusOK)
+	is.Equal(resp.TLS.PeerCertificates[0].Subject.CommonName, "mock")
+}
+
+func TestMutualTLSServer(t *testing.T) {
+	t.Parallel()
+
+	ca := generateCertificate(t, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "Test CA"},
+		KeyUsage: x509.KeyUsageCertSign,
+		IsCA:     true,
+
+		BasicConstraintsValid: true,
+	}, nil)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	clientCert := generateCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "client"},
+		DNSNames:    []string{"client.example.com"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &ca)
+
+	tests := []struct {
+		name   string
+		rule   gomockserver.MatchRule
+		status int
+	}{
+		{name: "Subject", rule: gomockserver.MatchClientCertificateSubject("client"), status: http.StatusOK},
+		{name: "Wrong subject", rule: gomockserver.MatchClientCertificateSubject("other"), status: http.StatusNotFound},
+		{name: "SAN", rule: gomockserver.MatchClientCertificateSAN("client.example.com"), status: http.StatusOK},
+		{name: "Wrong SAN", rule: gomockserver.MatchClientCertificateSAN("other.example.com"),
+			status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			server := gomockserver.New(t, gomockserver.WithClientCertificates(pool),
+				gomockserver.WithAllowUnmatchedRequests())
+			defer server.Close()
+
+			server.Matches(tt.rule)
+
+			_, err := server.Client().Get(server.URL()) //nolint:noctx,bodyclose
+			is.True(err != nil)
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				RootCAs:      server.CertPool(),
+				Certificates: []tls.Certificate{clientCert},
+				MinVersion:   tls.VersionTLS12,
+			}}}
+
+			resp, err := client.Get(server.URL()) //nolint:noctx
+			is.NoErr(err)
+
+			defer resp.Body.Close()
+
+			is.Equal(resp.StatusCode, tt.status)
+		})
+	}
+}
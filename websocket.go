@@ -0,0 +1,401 @@
+package gomockserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketMessageType represents the type of a message sent over a WebSocket connection.
+type WebSocketMessageType int
+
+const (
+	// WebSocketText represents a text message.
+	WebSocketText WebSocketMessageType = websocket.TextMessage
+	// WebSocketBinary represents a binary message.
+	WebSocketBinary WebSocketMessageType = websocket.BinaryMessage
+	// WebSocketClose represents a close message. The data of a recorded close message is the close code and reason.
+	WebSocketClose WebSocketMessageType = websocket.CloseMessage
+)
+
+// WebSocketDirection represents which way a message was sent over a WebSocket connection.
+type WebSocketDirection int
+
+const (
+	// WebSocketReceived represents a message that was received from the client.
+	WebSocketReceived WebSocketDirection = iota
+	// WebSocketSent represents a message that was sent to the client.
+	WebSocketSent
+)
+
+// WebSocketFrame represents a single message that was exchanged over a WebSocket connection.
+type WebSocketFrame struct {
+	// Connection is the index of the connection that the message was exchanged on, starting from zero.
+	Connection int
+	Direction  WebSocketDirection
+	Type       WebSocketMessageType
+	Data       []byte
+	Timestamp  time.Time
+}
+
+// webSocketStepKind represents what a single step of the scripted conversation with a WebSocket client does.
+type webSocketStepKind int
+
+const (
+	// webSocketExpect waits for the next message from the client and checks it.
+	webSocketExpect webSocketStepKind = iota
+	// webSocketSend sends a message to the client.
+	webSocketSend
+	// webSocketCloseStep closes the connection.
+	webSocketCloseStep
+)
+
+// webSocketStep represents a single step of the scripted conversation with a WebSocket client.
+type webSocketStep struct {
+	kind        webSocketStepKind
+	expect      MatchRules
+	send        *WebSocketFrame
+	closeCode   int
+	closeReason string
+}
+
+// WebSocketMock represents a WebSocket endpoint on the mock server. Every client that connects to it has the same
+// scripted conversation, made up of messages that are expected from the client, messages that are sent to the client
+// and finally closing the connection. Once the script is finished the connection is kept open - unless it was closed
+// by the script - until either the client closes it or the server is closed.
+type WebSocketMock struct {
+	reporter    Reporter
+	match       *Match
+	upgrader    websocket.Upgrader
+	mutex       sync.Mutex
+	script      []webSocketStep
+	frames      []WebSocketFrame
+	connections []*webSocketConnection
+	nextIndex   int
+}
+
+// WebSocket will create a new WebSocket endpoint on the server, which will accept upgrade requests that pass every
+// one of the provided rules.
+func (s *server) WebSocket(rules ...MatchRule) *WebSocketMock {
+	mock := &WebSocketMock{
+		reporter: s.reporter,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true
+			},
+		},
+	}
+
+	rules = append([]MatchRule{matchWebSocketUpgrade()}, rules...)
+	mock.match = s.Matches(rules...).RespondsWith(mock)
+
+	return mock
+}
+
+// matchWebSocketUpgrade builds a `MatchRule` to check that the request is a WebSocket upgrade request.
+func matchWebSocketUpgrade() MatchRule {
+	return MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		return websocket.IsWebSocketUpgrade(r),
+			fmt.Sprintf("WebSocket upgrade: expected upgrade request, got Connection %v and Upgrade %v",
+				r.Header.Values("Connection"), r.Header.Values("Upgrade"))
+	})
+}
+
+// Match returns the `Match` that accepts the upgrade requests for this endpoint, so that expectations can be declared
+// on the number of connections.
+func (w *WebSocketMock) Match() *Match {
+	return w.match
+}
+
+func (w *WebSocketMock) addStep(step webSocketStep) *WebSocketMock {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.script = append(w.script, step)
+
+	return w
+}
+
+// Expect adds a step to the script that waits for the next message from the client, and checks that it passes every
+// one of the provided rules. The message is presented to the rules as the body of a request, so any rule that
+// inspects the body - such as `MatchJSONCompatible` - can be used. The test fails if the message does not match.
+func (w *WebSocketMock) Expect(rules ...MatchRule) *WebSocketMock {
+	return w.addStep(webSocketStep{kind: webSocketExpect, expect: rules})
+}
+
+// SendText adds a step to the script that sends a text message to the client.
+func (w *WebSocketMock) SendText(message string) *WebSocketMock {
+	return w.addStep(webSocketStep{kind: webSocketSend, send: &WebSocketFrame{Type: WebSocketText, Data: []byte(message)}})
+}
+
+// SendBinary adds a step to the script that sends a binary message to the client.
+func (w *WebSocketMock) SendBinary(message []byte) *WebSocketMock {
+	return w.addStep(webSocketStep{kind: webSocketSend, send: &WebSocketFrame{Type: WebSocketBinary, Data: message}})
+}
+
+// SendJSON adds a step to the script that sends the JSON encoding of the provided value to the client as a text
+// message.
+func (w *WebSocketMock) SendJSON(data interface{}) *WebSocketMock {
+	message, _ := json.Marshal(data)
+
+	return w.SendText(string(message))
+}
+
+// Close adds a step to the script that closes the connection with the given close code and reason.
+func (w *WebSocketMock) Close(code int, reason string) *WebSocketMock {
+	return w.addStep(webSocketStep{kind: webSocketCloseStep, closeCode: code, closeReason: reason})
+}
+
+// Push will immediately send a text message to every client that is currently connected, regardless of where they
+// are in the script.
+func (w *WebSocketMock) Push(message string) {
+	w.push(WebSocketFrame{Type: WebSocketText, Data: []byte(message)})
+}
+
+// PushJSON will immediately send the JSON encoding of the provided value as a text message to every client that is
+// currently connected, regardless of where they are in the script.
+func (w *WebSocketMock) PushJSON(data interface{}) {
+	message, _ := json.Marshal(data)
+
+	w.Push(string(message))
+}
+
+func (w *WebSocketMock) push(frame WebSocketFrame) {
+	w.mutex.Lock()
+	connections := append([]*webSocketConnection{}, w.connections...)
+	w.mutex.Unlock()
+
+	for _, conn := range connections {
+		conn.send(frame)
+	}
+}
+
+// Connected returns the number of clients that are currently connected.
+func (w *WebSocketMock) Connected() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return len(w.connections)
+}
+
+// Frames returns every message that has been exchanged with any client, in the order they were exchanged.
+func (w *WebSocketMock) Frames() []WebSocketFrame {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return append([]WebSocketFrame{}, w.frames...)
+}
+
+func (w *WebSocketMock) record(frame WebSocketFrame) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	frame.Timestamp = time.Now()
+	w.frames = append(w.frames, frame)
+}
+
+func (w *WebSocketMock) PopulateResponse(r *Response, req *http.Request) {
+	r.upgrade = func(ctx context.Context, rw http.ResponseWriter) {
+		w.serve(ctx, rw, req)
+	}
+}
+
+// serve will upgrade the request to a WebSocket connection, and then run the scripted conversation on it.
+func (w *WebSocketMock) serve(ctx context.Context, rw http.ResponseWriter, req *http.Request) {
+	ws, err := w.upgrader.Upgrade(rw, req, nil)
+	if err != nil {
+		w.reporter.Errorf("Failed to upgrade WebSocket connection: %v", err)
+
+		return
+	}
+
+	w.mutex.Lock()
+	conn := &webSocketConnection{
+		mock:     w,
+		conn:     ws,
+		index:    w.nextIndex,
+		incoming: make(chan []byte),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	w.nextIndex++
+	w.connections = append(w.connections, conn)
+	script := append([]webSocketStep{}, w.script...)
+	w.mutex.Unlock()
+
+	defer w.disconnected(conn)
+
+	conn.run(ctx, script)
+}
+
+func (w *WebSocketMock) disconnected(conn *webSocketConnection) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for i, c := range w.connections {
+		if c == conn {
+			w.connections = append(w.connections[:i], w.connections[i+1:]...)
+
+			break
+		}
+	}
+}
+
+// webSocketConnection represents a single client connected to a `WebSocketMock`.
+type webSocketConnection struct {
+	mock      *WebSocketMock
+	conn      *websocket.Conn
+	index     int
+	writeLock sync.Mutex
+	incoming  chan []byte
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// run will run the scripted conversation on this connection, and then wait for the connection to finish.
+func (c *webSocketConnection) run(ctx context.Context, script []webSocketStep) {
+	defer c.conn.Close()
+	defer close(c.stopped)
+
+	c.conn.SetCloseHandler(func(code int, text string) error {
+		c.mock.record(WebSocketFrame{Connection: c.index, Direction: WebSocketReceived, Type: WebSocketClose,
+			Data: websocket.FormatCloseMessage(code, text)})
+
+		_ = c.writeClose(code, "")
+
+		return nil
+	})
+
+	go c.read()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.conn.Close()
+		case <-c.done:
+		}
+	}()
+
+	for i, step := range script {
+		switch step.kind {
+		case webSocketExpect:
+			message, ok := c.receive(ctx)
+			if !ok {
+				if ctx.Err() == nil {
+					c.mock.reporter.Errorf("WebSocket client closed the connection while waiting for message at step %d", i+1)
+				}
+
+				return
+			}
+
+			c.check(i, step.expect, message)
+		case webSocketSend:
+			c.send(*step.send)
+		case webSocketCloseStep:
+			_ = c.writeClose(step.closeCode, step.closeReason)
+		}
+	}
+
+	for {
+		if _, ok := c.receive(ctx); !ok {
+			return
+		}
+	}
+}
+
+// read will read every message from the client, recording them and passing them on to the script, until the
+// connection is closed.
+func (c *webSocketConnection) read() {
+	defer close(c.done)
+
+	for {
+		messageType, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		c.mock.record(WebSocketFrame{Connection: c.index, Direction: WebSocketReceived,
+			Type: WebSocketMessageType(messageType), Data: data})
+
+		select {
+		case c.incoming <- data:
+		case <-c.stopped:
+			return
+		}
+	}
+}
+
+// receive will wait for the next message from the client, returning `false` if the connection is closed first.
+func (c *webSocketConnection) receive(ctx context.Context) ([]byte, bool) {
+	select {
+	case message := <-c.incoming:
+		return message, true
+	case <-c.done:
+		return nil, false
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// check will fail the test if the message does not pass every one of the expected rules.
+func (c *webSocketConnection) check(step int, expected MatchRules, message []byte) {
+	req := RecordedRequest{
+		Method: http.MethodPost,
+		URL:    &url.URL{Path: "/"},
+		Header: http.Header{},
+		Body:   message,
+	}.Request()
+
+	if expected.Matches(req) {
+		return
+	}
+
+	reasons := []string{}
+
+	for _, rule := range flattenRules(expected) {
+		rewindBody(req)
+
+		matched, reason := explainRule(rule, req)
+		reasons = append(reasons, fmt.Sprintf("%s: %s", resultLabel(matched), reason))
+	}
+
+	c.mock.reporter.Errorf("WebSocket message did not match step %d: %s\n    %s", step+1, message,
+		strings.Join(reasons, "\n    "))
+}
+
+// send will send a message to the client.
+func (c *webSocketConnection) send(frame WebSocketFrame) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	if err := c.conn.WriteMessage(int(frame.Type), frame.Data); err != nil {
+		return
+	}
+
+	frame.Connection = c.index
+	frame.Direction = WebSocketSent
+	c.mock.record(frame)
+}
+
+// writeClose will send a close message to the client.
+func (c *webSocketConnection) writeClose(code int, reason string) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	message := websocket.FormatCloseMessage(code, reason)
+
+	if err := c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second)); err != nil {
+		return err
+	}
+
+	c.mock.record(WebSocketFrame{Connection: c.index, Direction: WebSocketSent, Type: WebSocketClose, Data: message})
+
+	return nil
+}
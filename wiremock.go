@@ -0,0 +1,626 @@
+package gomockserver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errWireMockMappings is returned when a WireMock mapping file does not have the expected structure.
+var errWireMockMappings = errors.New("mappings must be JSON objects")
+
+// wireMockDefaultPriority is the priority that WireMock gives to stubs that do not specify one.
+const wireMockDefaultPriority = 5
+
+// WireMockImportError reports every feature of a set of WireMock stub mappings that could not be imported.
+type WireMockImportError struct {
+	// Unsupported describes each feature that could not be imported, and which stub it was used in.
+	Unsupported []string
+}
+
+func (e *WireMockImportError) Error() string {
+	return fmt.Sprintf("unable to import %d WireMock features:\n    %s", len(e.Unsupported),
+		strings.Join(e.Unsupported, "\n    "))
+}
+
+// wireMockStub is a single WireMock stub mapping that is being imported.
+type wireMockStub struct {
+	label       string
+	priority    int
+	index       int
+	mock        Mock
+	unsupported []string
+	filesDir    string
+}
+
+// unsupportedf records that the stub uses a feature that can not be imported.
+func (s *wireMockStub) unsupportedf(format string, args ...interface{}) {
+	s.unsupported = append(s.unsupported, fmt.Sprintf("%s: %s", s.label, fmt.Sprintf(format, args...)))
+}
+
+// ImportWireMock will import the WireMock stub mappings from the provided JSON file, or from every `.json` file in the
+// provided directory in name order. If the directory contains a `mappings` directory then the mappings are loaded from
+// there instead. Files referenced by `bodyFileName` are loaded from the `__files` directory alongside the mappings
+// directory, as WireMock does. The mocks are returned ordered by the priority of the stubs, with stubs of the same
+// priority ordered from the last one loaded to the first since WireMock prefers the most recently added stub, and can
+// then be mounted using `MockServer.Mount`.
+//
+// The following parts of the request patterns are supported:
+//
+//	method, url, urlPattern, urlPath, urlPathPattern
+//	headers and queryParameters, using equalTo, caseInsensitive, matches, doesNotMatch, contains and absent
+//	bodyPatterns, using equalTo, contains, matches, equalToJson with ignoreExtraElements, and matchesJsonPath with
+//	simple expressions optionally combined with equalTo, contains or matches
+//
+// And the following parts of the responses are supported:
+//
+//	status, headers, body, base64Body, jsonBody, bodyFileName, fixedDelayMilliseconds
+//
+// Any stub that uses any other feature is not imported, and every unsupported feature is reported by returning a
+// `*WireMockImportError` alongside the mocks for the stubs that could be imported.
+func ImportWireMock(path string) ([]Mock, error) {
+	files, filesDir, err := wireMockFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	stubs := []*wireMockStub{}
+	unsupported := []string{}
+
+	for _, file := range files {
+		loaded, err := loadWireMockFile(file, filesDir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, stub := range loaded {
+			if len(stub.unsupported) > 0 {
+				unsupported = append(unsupported, stub.unsupported...)
+
+				continue
+			}
+
+			stub.index = len(stubs)
+			stubs = append(stubs, stub)
+		}
+	}
+
+	sort.Slice(stubs, func(i, j int) bool {
+		if stubs[i].priority != stubs[j].priority {
+			return stubs[i].priority < stubs[j].priority
+		}
+
+		return stubs[i].index > stubs[j].index
+	})
+
+	mocks := make([]Mock, 0, len(stubs))
+	for _, stub := range stubs {
+		mocks = append(mocks, stub.mock)
+	}
+
+	if len(unsupported) > 0 {
+		return mocks, &WireMockImportError{Unsupported: unsupported}
+	}
+
+	return mocks, nil
+}
+
+// wireMockFiles finds every mapping file to import from the provided path, and the directory that body files are
+// loaded from.
+func wireMockFiles(path string) ([]string, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, filepath.Join(filepath.Dir(path), "..", "__files"), nil
+	}
+
+	if info, err := os.Stat(filepath.Join(path, "mappings")); err == nil && info.IsDir() {
+		path = filepath.Join(path, "mappings")
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	files := []string{}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+
+	return files, filepath.Join(path, "..", "__files"), nil
+}
+
+// loadWireMockFile will load every stub mapping from a single file, which can contain either a single mapping or an
+// object with a `mappings` array.
+func loadWireMockFile(path, filesDir string) ([]*wireMockStub, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	mappings := []interface{}{file}
+
+	if list, ok := file["mappings"]; ok {
+		if mappings, ok = list.([]interface{}); !ok {
+			return nil, fmt.Errorf("%s: %w", path, errWireMockMappings)
+		}
+	}
+
+	stubs := make([]*wireMockStub, 0, len(mappings))
+
+	for i, mapping := range mappings {
+		label := fmt.Sprintf("%s mapping %d", path, i+1)
+
+		fields, ok := mapping.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", label, errWireMockMappings)
+		}
+
+		if name, ok := fields["name"].(string); ok {
+			label = fmt.Sprintf("%s (%s)", label, name)
+		}
+
+		stub := &wireMockStub{label: label, priority: wireMockDefaultPriority, filesDir: filesDir}
+		stub.translate(fields)
+
+		stubs = append(stubs, stub)
+	}
+
+	return stubs, nil
+}
+
+// translate converts the fields of a WireMock stub mapping into the mock, recording any unsupported features.
+func (s *wireMockStub) translate(fields map[string]interface{}) {
+	for _, key := range sortedJSONKeys(fields) {
+		value := fields[key]
+
+		switch key {
+		case "request":
+			if request, ok := s.jsonObject("request", value); ok {
+				s.translateRequest(request)
+			}
+		case "response":
+			if response, ok := s.jsonObject("response", value); ok {
+				s.translateResponse(response)
+			}
+		case "priority":
+			if priority, ok := asJSONInt(value); ok {
+				s.priority = priority
+			} else {
+				s.unsupportedf("priority %v is not a number", value)
+			}
+		case "id", "uuid", "name", "metadata", "persistent":
+		default:
+			s.unsupportedf("%s is not supported", key)
+		}
+	}
+}
+
+// translateRequest converts a WireMock request pattern into the rules for the mock.
+func (s *wireMockStub) translateRequest(request map[string]interface{}) {
+	for _, key := range sortedJSONKeys(request) {
+		value := request[key]
+
+		switch key {
+		case "method":
+			if method, ok := s.jsonString("request.method", value); ok && method != "ANY" {
+				s.addRule(MatchMethod(method))
+			}
+		case "url":
+			url, ok := s.jsonString("request.url", value)
+			if !ok {
+				continue
+			}
+
+			s.addRule(MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+				return r.RequestURI == url, fmt.Sprintf("URL: expected %s, got %s", url, r.RequestURI)
+			}))
+		case "urlPattern":
+			pattern, ok := s.jsonString("request.urlPattern", value)
+			if !ok {
+				continue
+			}
+
+			if re, ok := s.compileRegex("request.urlPattern", pattern); ok {
+				s.addRule(MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+					return re.MatchString(r.RequestURI),
+						fmt.Sprintf("URL: expected to match regex %s, got %s", pattern, r.RequestURI)
+				}))
+			}
+		case "urlPath":
+			if path, ok := s.jsonString("request.urlPath", value); ok {
+				s.addRule(MatchURLPath(path))
+			}
+		case "urlPathPattern":
+			pattern, ok := s.jsonString("request.urlPathPattern", value)
+			if !ok {
+				continue
+			}
+
+			if _, ok := s.compileRegex("request.urlPathPattern", pattern); ok {
+				s.addRule(MatchURLPathRegex(fullRegex(pattern)))
+			}
+		case "headers":
+			if patterns, ok := s.jsonObject("request.headers", value); ok {
+				s.translateValuePatterns("request.headers", patterns, matchHeader)
+			}
+		case "queryParameters":
+			if patterns, ok := s.jsonObject("request.queryParameters", value); ok {
+				s.translateValuePatterns("request.queryParameters", patterns, matchURLQuery)
+			}
+		case "bodyPatterns":
+			patterns, ok := value.([]interface{})
+			if !ok {
+				s.unsupportedf("request.bodyPatterns must be a JSON array, got %s", jsonString(value))
+			}
+
+			for i, pattern := range patterns {
+				location := fmt.Sprintf("request.bodyPatterns[%d]", i)
+
+				if object, ok := s.jsonObject(location, pattern); ok {
+					s.translateBodyPattern(location, object)
+				}
+			}
+		default:
+			s.unsupportedf("request.%s is not supported", key)
+		}
+	}
+}
+
+// addRule adds a new rule that requests must pass to match the mock.
+func (s *wireMockStub) addRule(rule MatchRule) {
+	s.mock.Matches = append(s.mock.Matches, rule)
+}
+
+// compileRegex compiles a WireMock regular expression, which must match the entire value, recording it as
+// unsupported if it is not valid in Go.
+func (s *wireMockStub) compileRegex(location, pattern string) (*regexp.Regexp, bool) {
+	re, err := regexp.Compile(fullRegex(pattern))
+	if err != nil {
+		s.unsupportedf("%s regex %s is not supported: %v", location, pattern, err)
+
+		return nil, false
+	}
+
+	return re, true
+}
+
+// fullRegex anchors the provided regular expression so that it must match the entire value, as WireMock requires.
+func fullRegex(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+// translateValuePatterns converts the WireMock patterns for named values - such as headers or query parameters - into
+// rules built by the provided function.
+func (s *wireMockStub) translateValuePatterns(location string, patterns map[string]interface{},
+	build func(name, expected string, matcher func(values []string) bool) MatchRule) {
+	for _, name := range sortedJSONKeys(patterns) {
+		pattern, ok := s.jsonObject(fmt.Sprintf("%s.%s", location, name), patterns[name])
+		if !ok {
+			continue
+		}
+
+		expected, matcher, ok := s.valuePattern(fmt.Sprintf("%s.%s", location, name), pattern)
+		if ok {
+			s.addRule(build(name, expected, matcher))
+		}
+	}
+}
+
+// valuePattern converts a single WireMock string value pattern into a function to check the values it applies to,
+// along with a description of what it expects.
+func (s *wireMockStub) valuePattern(location string, pattern map[string]interface{}) (string, func([]string) bool,
+	bool) {
+	caseInsensitive, _ := pattern["caseInsensitive"].(bool)
+
+	for _, key := range sortedJSONKeys(pattern) {
+		value, isString := pattern[key].(string)
+
+		switch key {
+		case "equalTo", "contains", "matches", "doesNotMatch":
+			if !isString {
+				s.unsupportedf("%s.%s must be a string, got %s", location, key, jsonString(pattern[key]))
+
+				return "", nil, false
+			}
+		}
+
+		switch key {
+		case "caseInsensitive":
+			continue
+		case "equalTo":
+			return value, func(values []string) bool {
+				return anyValue(values, func(v string) bool {
+					return v == value || (caseInsensitive && strings.EqualFold(v, value))
+				})
+			}, true
+		case "contains":
+			return "to contain " + value, func(values []string) bool {
+				return anyValue(values, func(v string) bool {
+					return strings.Contains(v, value)
+				})
+			}, true
+		case "matches", "doesNotMatch":
+			re, ok := s.compileRegex(location+"."+key, value)
+			if !ok {
+				return "", nil, false
+			}
+
+			if key == "doesNotMatch" {
+				return "to not match regex " + value, func(values []string) bool {
+					return len(values) > 0 && !anyValue(values, re.MatchString)
+				}, true
+			}
+
+			return "to match regex " + value, func(values []string) bool {
+				return anyValue(values, re.MatchString)
+			}, true
+		case "absent":
+			absent, _ := pattern[key].(bool)
+
+			return "to be absent", func(values []string) bool {
+				return (len(values) == 0) == absent
+			}, true
+		default:
+			s.unsupportedf("%s.%s is not supported", location, key)
+
+			return "", nil, false
+		}
+	}
+
+	s.unsupportedf("%s has no supported pattern", location)
+
+	return "", nil, false
+}
+
+// translateBodyPattern converts a single WireMock body pattern into a rule for the mock.
+func (s *wireMockStub) translateBodyPattern(location string, pattern map[string]interface{}) {
+	switch {
+	case pattern["equalToJson"] != nil:
+		s.translateEqualToJSON(location, pattern)
+	case pattern["matchesJsonPath"] != nil:
+		s.translateMatchesJSONPath(location, pattern)
+	default:
+		expected, matcher, ok := s.valuePattern(location, pattern)
+		if ok {
+			s.addRule(MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+				body := string(RequestBody(r))
+
+				return matcher([]string{body}), fmt.Sprintf("body: expected %s, got %s", expected, body)
+			}))
+		}
+	}
+}
+
+// translateEqualToJSON converts a WireMock `equalToJson` body pattern into a rule for the mock.
+func (s *wireMockStub) translateEqualToJSON(location string, pattern map[string]interface{}) {
+	expected := pattern["equalToJson"]
+
+	if text, ok := expected.(string); ok {
+		if err := json.Unmarshal([]byte(text), &expected); err != nil {
+			s.unsupportedf("%s.equalToJson is not valid JSON: %v", location, err)
+
+			return
+		}
+	}
+
+	for _, key := range sortedJSONKeys(pattern) {
+		switch key {
+		case "equalToJson", "ignoreExtraElements":
+		default:
+			if enabled, ok := pattern[key].(bool); !ok || enabled {
+				s.unsupportedf("%s.%s is not supported", location, key)
+
+				return
+			}
+		}
+	}
+
+	if ignoreExtra, _ := pattern["ignoreExtraElements"].(bool); ignoreExtra {
+		s.addRule(MatchJSONCompatible(expected))
+	} else {
+		s.addRule(MatchJSONFull(expected))
+	}
+}
+
+// translateMatchesJSONPath converts a WireMock `matchesJsonPath` body pattern into a rule for the mock.
+func (s *wireMockStub) translateMatchesJSONPath(location string, pattern map[string]interface{}) {
+	expression, ok := pattern["matchesJsonPath"].(string)
+	valueMatcher := func(values []string) bool {
+		return len(values) > 0
+	}
+	expected := "to exist"
+
+	if !ok {
+		object, isObject := pattern["matchesJsonPath"].(map[string]interface{})
+		if !isObject {
+			s.unsupportedf("%s.matchesJsonPath must be a string or a JSON object, got %s", location,
+				jsonString(pattern["matchesJsonPath"]))
+
+			return
+		}
+
+		expression, ok = object["expression"].(string)
+		if !ok {
+			s.unsupportedf("%s.matchesJsonPath has no expression", location)
+
+			return
+		}
+
+		delete(object, "expression")
+
+		if len(object) > 0 {
+			if expected, valueMatcher, ok = s.valuePattern(location+".matchesJsonPath", object); !ok {
+				return
+			}
+		}
+	}
+
+	steps, err := parseJSONPath(expression)
+	if err != nil {
+		s.unsupportedf("%s.matchesJsonPath %v", location, err)
+
+		return
+	}
+
+	s.addRule(MatchRuleExplainerFunc(func(r *http.Request) (bool, string) {
+		var document interface{}
+		if err := json.Unmarshal(RequestBody(r), &document); err != nil {
+			return false, fmt.Sprintf("JSON path %s: unable to parse body: %v", expression, err)
+		}
+
+		values := []string{}
+		for _, value := range evaluateJSONPath(document, steps) {
+			values = append(values, jsonPathString(value))
+		}
+
+		return valueMatcher(values), fmt.Sprintf("JSON path %s: expected %s, got %v", expression, expected, values)
+	}))
+}
+
+// translateResponse converts a WireMock response definition into the response builders for the mock.
+func (s *wireMockStub) translateResponse(response map[string]interface{}) {
+	body := []ResponseBuilder{}
+	status := []ResponseBuilder{}
+	headers := []ResponseBuilder{}
+
+	for _, key := range sortedJSONKeys(response) {
+		value := response[key]
+
+		switch key {
+		case "status":
+			if code, ok := asJSONInt(value); ok {
+				status = append(status, ResponseStatus(code))
+			} else {
+				s.unsupportedf("response.status %v is not a number", value)
+			}
+		case "headers":
+			header, ok := s.jsonObject("response.headers", value)
+			if !ok {
+				continue
+			}
+
+			for _, name := range sortedJSONKeys(header) {
+				headers = append(headers, wireMockHeader(name, header[name]))
+			}
+		case "body":
+			if text, ok := s.jsonString("response.body", value); ok {
+				body = append(body, ResponseBody([]byte(text)))
+			}
+		case "base64Body":
+			encoded, ok := s.jsonString("response.base64Body", value)
+			if !ok {
+				continue
+			}
+
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				s.unsupportedf("response.base64Body is not valid base64: %v", err)
+			}
+
+			body = append(body, ResponseBody(decoded))
+		case "jsonBody":
+			body = append(body, ResponseJSON(value))
+		case "bodyFileName":
+			name, ok := s.jsonString("response.bodyFileName", value)
+			if !ok {
+				continue
+			}
+
+			path := filepath.Join(s.filesDir, name)
+
+			if info, err := os.Stat(path); err != nil || info.IsDir() {
+				s.unsupportedf("response.bodyFileName %s does not exist", path)
+			}
+
+			body = append(body, ResponseFile(path))
+		case "fixedDelayMilliseconds":
+			if delay, ok := asJSONInt(value); ok {
+				status = append(status, ResponseDelay(time.Duration(delay)*time.Millisecond))
+			} else {
+				s.unsupportedf("response.fixedDelayMilliseconds %v is not a number", value)
+			}
+		default:
+			s.unsupportedf("response.%s is not supported", key)
+		}
+	}
+
+	s.mock.Response = append(append(body, status...), headers...)
+}
+
+// wireMockHeader builds a response builder for a WireMock response header, which can have a single value or an array
+// of values.
+func wireMockHeader(name string, value interface{}) ResponseBuilder {
+	values, ok := value.([]interface{})
+	if !ok {
+		return ResponseSetHeader(name, fmt.Sprint(value))
+	}
+
+	builders := ResponseBuilders{ResponseBuilderFunc(func(r *Response, req *http.Request) {
+		r.Headers.Del(name)
+	})}
+
+	for _, v := range values {
+		builders = append(builders, ResponseAppendHeader(name, fmt.Sprint(v)))
+	}
+
+	return builders
+}
+
+// jsonObject converts a decoded JSON value into an object, recording it as unsupported if it is not one.
+func (s *wireMockStub) jsonObject(location string, value interface{}) (map[string]interface{}, bool) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		s.unsupportedf("%s must be a JSON object, got %s", location, jsonString(value))
+	}
+
+	return object, ok
+}
+
+// jsonString converts a decoded JSON value into a string, recording it as unsupported if it is not one.
+func (s *wireMockStub) jsonString(location string, value interface{}) (string, bool) {
+	text, ok := value.(string)
+	if !ok {
+		s.unsupportedf("%s must be a string, got %s", location, jsonString(value))
+	}
+
+	return text, ok
+}
+
+// asJSONInt converts a decoded JSON value into an integer, if it is one.
+func asJSONInt(value interface{}) (int, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	i, err := number.Int64()
+
+	return int(i), err == nil
+}
@@ -0,0 +1,191 @@
+package gomockserver_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/sazzer/gomockserver"
+)
+
+func TestImportWireMock(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	mocks, err := gomockserver.ImportWireMock("testdata/wiremock")
+	is.NoErr(err)
+	is.Equal(len(mocks), 3)
+
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
+	defer server.Close()
+
+	for _, mock := range mocks {
+		server.Mount(mock)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		url     string
+		headers map[string]string
+		body    string
+		status  int
+		output  string
+		expect  http.Header
+		delay   time.Duration
+	}{
+		{name: "Body file", method: http.MethodGet, url: "/users/1",
+			headers: map[string]string{"Accept": "application/json"}, status: http.StatusOK,
+			output: "{\"id\":1,\"name\":\"Test\"}\n",
+			expect: http.Header{"X-Source": {"wiremock", "file"}, "Content-Type": {"application/json"}}},
+		{name: "Path pattern mismatch", method: http.MethodGet, url: "/users/abc",
+			headers: map[string]string{"Accept": "application/json"}, status: http.StatusNotFound},
+		{name: "Header contains mismatch", method: http.MethodGet, url: "/users/1",
+			headers: map[string]string{"Accept": "text/html"}, status: http.StatusNotFound},
+		{name: "Header absent mismatch", method: http.MethodGet, url: "/users/1",
+			headers: map[string]string{"Accept": "application/json", "X-Debug": "1"}, status: http.StatusNotFound},
+		{name: "JSON body", method: http.MethodPost, url: "/users",
+			headers: map[string]string{"Authorization": "Bearer abc"},
+			body:    `{"name":"Test","tags":["a"],"address":{"city":"London"}}`, status: http.StatusCreated,
+			output: `{"id":2,"name":"Test"}`},
+		{name: "JSON path missing", method: http.MethodPost, url: "/users",
+			headers: map[string]string{"Authorization": "Bearer abc"},
+			body:    `{"name":"Test","tags":[],"address":{"city":"London"}}`, status: http.StatusNotFound},
+		{name: "JSON path value mismatch", method: http.MethodPost, url: "/users",
+			headers: map[string]string{"Authorization": "Bearer abc"},
+			body:    `{"name":"Test","tags":["a"],"address":{"city":"Paris"}}`, status: http.StatusNotFound},
+		{name: "Header regex mismatch", method: http.MethodPost, url: "/users",
+			headers: map[string]string{"Authorization": "Basic abc"},
+			body:    `{"name":"Test","tags":["a"],"address":{"city":"London"}}`, status: http.StatusNotFound},
+		{name: "URL and delay", method: http.MethodDelete, url: "/search?q=widgets", status: http.StatusOK,
+			output: "Found widgets", expect: http.Header{"Content-Type": {"text/plain"}}, delay: 50 * time.Millisecond},
+		{name: "URL mismatch", method: http.MethodGet, url: "/search?q=widgets&page=2", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			req, err := http.NewRequestWithContext(context.Background(), tt.method, server.URL()+tt.url,
+				strings.NewReader(tt.body))
+			is.NoErr(err)
+
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			start := time.Now()
+
+			resp, err := http.DefaultClient.Do(req)
+			is.NoErr(err)
+
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			is.NoErr(err)
+
+			is.Equal(resp.StatusCode, tt.status)
+
+			if tt.output != "" {
+				is.Equal(string(body), tt.output)
+			}
+
+			for name, values := range tt.expect {
+				is.Equal(resp.Header.Values(name), values)
+			}
+
+			is.True(time.Since(start) >= tt.delay)
+		})
+	}
+}
+
+func TestImportWireMockUnsupported(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	mocks, err := gomockserver.ImportWireMock("testdata/wiremock-unsupported/mappings/stubs.json")
+	is.Equal(len(mocks), 1)
+
+	var importError *gomockserver.WireMockImportError
+	is.True(errors.As(err, &importError))
+
+	file := "testdata/wiremock-unsupported/mappings/stubs.json"
+
+	is.Equal(importError.Unsupported, []string{
+		file + ` mapping 2 (Scenario): request.bodyPatterns[0].matchesJsonPath unsupported JSON path "$..id": ` +
+			`recursive descent is not supported`,
+		file + " mapping 2 (Scenario): response.transformers is not supported",
+		file + " mapping 2 (Scenario): scenarioName is not supported",
+		file + " mapping 3 (Missing file): request.headers.Accept.equalToXml is not supported",
+		file + " mapping 3 (Missing file): response.bodyFileName " +
+			"testdata/wiremock-unsupported/__files/missing.json does not exist",
+		file + " mapping 3 (Missing file): response.fault is not supported",
+		file + ` mapping 4 (Wrong body patterns): request.bodyPatterns must be a JSON array, got {"equalTo":"wrong"}`,
+		file + ` mapping 4 (Wrong body patterns): request.headers.Accept must be a JSON object, got "application/json"`,
+		file + ` mapping 4 (Wrong body patterns): response must be a JSON object, got "OK"`,
+		file + ` mapping 5 (Wrong request): request must be a JSON object, got "/wrong"`,
+		file + ` mapping 6 (Wrong method): request.method must be a string, got ["GET","POST"]`,
+		file + ` mapping 7 (Wrong URLs): request.url must be a string, got 1`,
+		file + ` mapping 7 (Wrong URLs): request.urlPath must be a string, got {"equalTo":"/wrong"}`,
+		file + ` mapping 7 (Wrong URLs): request.urlPathPattern must be a string, got true`,
+		file + ` mapping 7 (Wrong URLs): request.urlPattern must be a string, got ["/wrong.*"]`,
+		file + ` mapping 8 (Wrong value patterns): request.headers.Accept.equalTo must be a string, got 1`,
+		file + ` mapping 8 (Wrong value patterns): request.headers.Content-Type.contains must be a string, got ["json"]`,
+		file + ` mapping 8 (Wrong value patterns): request.queryParameters.page.matches must be a string, got 1`,
+		file + ` mapping 8 (Wrong value patterns): request.queryParameters.size.doesNotMatch must be a string, ` +
+			`got null`,
+		file + ` mapping 9 (Wrong response bodies): response.base64Body must be a string, got 1`,
+		file + ` mapping 9 (Wrong response bodies): response.body must be a string, got {"a":1}`,
+		file + ` mapping 9 (Wrong response bodies): response.bodyFileName must be a string, got ["user.json"]`,
+	})
+
+	server := gomockserver.New(t)
+	defer server.Close()
+
+	server.Mount(mocks[0])
+
+	resp := makeRequest(t, http.MethodGet, server.URL()+"/supported")
+	defer resp.Body.Close()
+
+	is.Equal(resp.StatusCode, http.StatusNoContent)
+}
+
+func TestImportWireMockPriority(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	mocks, err := gomockserver.ImportWireMock("testdata/wiremock-priority/stubs.json")
+	is.NoErr(err)
+	is.Equal(len(mocks), 3)
+
+	server := gomockserver.New(t, gomockserver.WithAllowUnmatchedRequests())
+	defer server.Close()
+
+	for _, mock := range mocks {
+		server.Mount(mock)
+	}
+
+	tests := []struct {
+		url    string
+		output string
+	}{
+		{url: "/greeting", output: "Second"},
+		{url: "/greetings", output: "Fallback"},
+	}
+
+	for _, tt := range tests {
+		resp := makeRequest(t, http.MethodGet, server.URL()+tt.url)
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		is.NoErr(err)
+		is.Equal(string(body), tt.output)
+	}
+}